@@ -0,0 +1,311 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// atsItem is a piece of the resume an applicant tracking system must be
+// able to read back from the PDF.
+type atsItem struct {
+	Kind string
+	Text string
+}
+
+// atsFinding is the result of looking for one item in the extracted text.
+type atsFinding struct {
+	atsItem
+	Status string // ok, missing, out of order, run together, not in resume
+}
+
+func (f atsFinding) ok() bool {
+	return f.Status == "ok"
+}
+
+var ligatures = strings.NewReplacer("ﬀ", "ff", "ﬁ", "fi", "ﬂ", "fl", "ﬃ", "ffi", "ﬄ", "ffl", "ﬅ", "st", "ﬆ", "st")
+
+var (
+	atsTokenRe      = regexp.MustCompile(`[a-z0-9+#]+`)
+	hyphenBreakRe   = regexp.MustCompile(`[A-Za-z]+-\n[a-z]+`)
+	strongMatchesRe = regexp.MustCompile(`(?s)Strong Matches:\n(.*?)(?:\n\n|$)`)
+)
+
+// atsNormalize lowercases text and reduces it to space-separated words so
+// spacing, dashes and line breaks don't matter.
+func atsNormalize(s string) string {
+	return strings.Join(atsTokenRe.FindAllString(strings.ToLower(ligatures.Replace(s)), -1), " ")
+}
+
+// atsExpected lists the contact details, section headings, employers and
+// dates of doc in the order they are printed.
+func atsExpected(doc *ResumeDoc) []atsItem {
+	items := []atsItem{{"name", latexPlain(doc.Name)}}
+	for _, line := range doc.Address {
+		for _, part := range strings.Split(line, `\\`) {
+			if text := latexPlain(part); text != "" {
+				items = append(items, atsItem{"contact", text})
+			}
+		}
+	}
+	for _, s := range doc.Sections {
+		items = append(items, atsItem{"heading", latexPlain(s.Title)})
+		for _, b := range s.Blocks {
+			if b.Role == nil {
+				continue
+			}
+			items = append(items, atsItem{"employer", latexPlain(b.Role.Employer)})
+			if dates := latexPlain(b.Role.Dates); dates != "" {
+				items = append(items, atsItem{"dates", dates})
+			}
+		}
+	}
+	return items
+}
+
+// strongMatchKeywords returns the technologies and proper nouns named in
+// each strong match, or its content words when it names none.
+func strongMatchKeywords(matches []string) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, m := range matches {
+		var terms []string
+		for i, tok := range driftTokens(m) {
+			if looksLikeTerm(tok, i == 0) {
+				terms = append(terms, tok)
+			}
+		}
+		if len(terms) == 0 {
+			for _, tok := range driftTokens(m) {
+				if !driftStopwords[strings.ToLower(tok)] && len(tok) > 3 {
+					terms = append(terms, tok)
+				}
+			}
+		}
+		for _, t := range terms {
+			t = strings.TrimRight(t, ".")
+			if !seen[strings.ToLower(t)] {
+				seen[strings.ToLower(t)] = true
+				keywords = append(keywords, t)
+			}
+		}
+	}
+	return keywords
+}
+
+// checkATSText looks for every expected item in the extracted text, in
+// order, and for every keyword anywhere. source is the resume's plain text,
+// used to tell extraction losses from keywords the resume never had.
+func checkATSText(extracted string, expected []atsItem, keywords []string, source string) []atsFinding {
+	text := " " + atsNormalize(extracted) + " "
+	squashed := strings.ReplaceAll(text, " ", "")
+
+	// Each item is looked for after the previous one first, so repeated
+	// words don't match early. Items off the longest increasing run of
+	// positions were read back out of place.
+	var findings []atsFinding
+	var positions []int
+	last := 0
+	for _, item := range expected {
+		needle := " " + atsNormalize(item.Text) + " "
+		if needle == "  " {
+			continue
+		}
+		f := atsFinding{atsItem: item, Status: "ok"}
+		pos := strings.Index(text[last:], needle)
+		if pos >= 0 {
+			pos += last
+		} else {
+			pos = strings.Index(text, needle)
+		}
+		if pos >= 0 {
+			last = pos + 1
+		} else if strings.Contains(squashed, strings.ReplaceAll(needle, " ", "")) {
+			f.Status = "run together"
+		} else {
+			f.Status = "missing"
+		}
+		findings = append(findings, f)
+		positions = append(positions, pos)
+	}
+	inOrder := longestIncreasing(positions)
+	for i, pos := range positions {
+		if pos >= 0 && !inOrder[i] {
+			findings[i].Status = "out of order"
+		}
+	}
+
+	source = " " + atsNormalize(source) + " "
+	for _, k := range keywords {
+		needle := " " + atsNormalize(k) + " "
+		if needle == "  " {
+			continue
+		}
+		f := atsFinding{atsItem: atsItem{"keyword", k}, Status: "ok"}
+		switch {
+		case strings.Contains(text, needle):
+		case !strings.Contains(source, needle):
+			f.Status = "not in resume"
+		case strings.Contains(squashed, strings.ReplaceAll(needle, " ", "")):
+			f.Status = "run together"
+		default:
+			f.Status = "missing"
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+// longestIncreasing marks the longest strictly increasing subsequence of the
+// non-negative values in positions. Ties go to the run ending latest, so an
+// item moved to the front is the one reported as moved.
+func longestIncreasing(positions []int) []bool {
+	n := len(positions)
+	length := make([]int, n)
+	prev := make([]int, n)
+	best := -1
+	for i, p := range positions {
+		prev[i] = -1
+		if p < 0 {
+			continue
+		}
+		length[i] = 1
+		for j := 0; j < i; j++ {
+			if positions[j] >= 0 && positions[j] < p && length[j]+1 > length[i] {
+				length[i], prev[i] = length[j]+1, j
+			}
+		}
+		if best < 0 || length[i] >= length[best] {
+			best = i
+		}
+	}
+	marked := make([]bool, n)
+	for i := best; i >= 0; i = prev[i] {
+		marked[i] = true
+	}
+	return marked
+}
+
+// atsWarnings flags extraction hazards that don't lose an item outright.
+func atsWarnings(extracted string) []string {
+	var warnings []string
+	var ligs []string
+	for _, l := range []string{"ﬀ", "ﬁ", "ﬂ", "ﬃ", "ﬄ", "ﬅ", "ﬆ"} {
+		if strings.Contains(extracted, l) {
+			ligs = append(ligs, l)
+		}
+	}
+	if len(ligs) > 0 {
+		warnings = append(warnings, fmt.Sprintf("ligature glyphs in text (%s): some ATS won't match words containing them; disable them in the template, e.g. with microtype's \\DisableLigatures", strings.Join(ligs, " ")))
+	}
+	if words := hyphenBreakRe.FindAllString(extracted, -1); len(words) > 0 {
+		for i, w := range words {
+			words[i] = strings.ReplaceAll(w, "\n", "")
+		}
+		warnings = append(warnings, fmt.Sprintf("words hyphenated across lines: %s", strings.Join(words, ", ")))
+	}
+	return warnings
+}
+
+func atsReport(findings []atsFinding, warnings []string) string {
+	var b strings.Builder
+	failed := 0
+	for _, f := range findings {
+		if !f.ok() {
+			failed++
+		}
+	}
+	fmt.Fprintf(&b, "ATS check: %d/%d items read back\n", len(findings)-failed, len(findings))
+
+	for _, kind := range []string{"name", "contact", "heading", "employer", "dates", "keyword"} {
+		var lines []string
+		for _, f := range findings {
+			if f.Kind == kind {
+				lines = append(lines, fmt.Sprintf("  [%s] %s", f.Status, f.Text))
+			}
+		}
+		if len(lines) > 0 {
+			fmt.Fprintf(&b, "\n%s:\n%s\n", atsKindTitles[kind], strings.Join(lines, "\n"))
+		}
+	}
+	if len(warnings) > 0 {
+		b.WriteString("\nWarnings:\n")
+		for _, w := range warnings {
+			fmt.Fprintf(&b, "  - %s\n", w)
+		}
+	}
+	return b.String()
+}
+
+var atsKindTitles = map[string]string{
+	"name":     "Name",
+	"contact":  "Contact details",
+	"heading":  "Section headings",
+	"employer": "Employers",
+	"dates":    "Dates",
+	"keyword":  "Strong-match keywords",
+}
+
+func runATSCheck(cmd *cobra.Command, args []string) {
+	dir := args[0]
+	src, err := os.ReadFile(filepath.Join(dir, "resume.tex"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading resume.tex: %v\n", err)
+		os.Exit(1)
+	}
+	pdfPath := filepath.Join(dir, "resume.pdf")
+	if _, err := os.Stat(pdfPath); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s not found (run 'resumectl pdf %s' first)\n", pdfPath, dir)
+		os.Exit(1)
+	}
+	extracted, err := extractPDFText(pdfPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	doc, err := parseResume(string(src))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing resume.tex: %v\n", err)
+		os.Exit(1)
+	}
+
+	var matches []string
+	if report, err := os.ReadFile(filepath.Join(dir, "report.txt")); err == nil {
+		if m := strongMatchesRe.FindStringSubmatch(string(report)); m != nil {
+			for _, line := range strings.Split(m[1], "\n") {
+				if item := strings.TrimPrefix(strings.TrimSpace(line), "- "); item != "" {
+					matches = append(matches, item)
+				}
+			}
+		}
+	}
+
+	findings := checkATSText(extracted, atsExpected(doc), strongMatchKeywords(matches), latexPlain(string(src)))
+	warnings := atsWarnings(extracted)
+	report := atsReport(findings, warnings)
+	if err := os.WriteFile(filepath.Join(dir, "ats-report.txt"), []byte(report), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing ats-report.txt: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, f := range findings {
+		if !f.ok() {
+			failed++
+			fmt.Printf("%s %s %q: %s\n", color.RedString("✗"), f.Kind, f.Text, f.Status)
+		}
+	}
+	for _, w := range warnings {
+		fmt.Printf("%s %s\n", color.YellowString("⚠"), w)
+	}
+	if failed > 0 {
+		fmt.Printf("\n%s %d of %d items did not read back. See %s/ats-report.txt\n", color.RedString("✗"), failed, len(findings), dir)
+		os.Exit(1)
+	}
+	fmt.Printf("%s All %d items read back in order. See %s/ats-report.txt\n", color.GreenString("✓"), len(findings), dir)
+}
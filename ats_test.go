@@ -0,0 +1,87 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleExtracted = `JANE DOE
+San Francisco, CA
+jane@example.com
+EXPERIENCE
+Acme Corp Jan 2021 – Present
+Staff Data Engineer Remote
+• Built a streaming platform on Kafka and Flink processing 2B events/day
+Initech 2017 – 2020
+TECHNICAL STRENGTHS
+Languages Go, Python, SQL, Scala
+PROJECTS
+EDUCATION
+State University 2017`
+
+func TestCheckATSText(t *testing.T) {
+	doc, err := parseResume(sampleResume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := atsExpected(doc)
+	source := latexPlain(sampleResume)
+
+	tests := []struct {
+		name      string
+		extracted string
+		keywords  []string
+		want      map[string]string
+	}{
+		{"clean", sampleExtracted, []string{"Kafka", "Python"}, nil},
+		{"ligatures are read through", strings.Replace(sampleExtracted, "Staff Data", "Staﬀ Data", 1), []string{"Staff"}, nil},
+		{"claimed keyword not in resume", sampleExtracted, []string{"Terraform"},
+			map[string]string{"Terraform": "not in resume"}},
+		{"header text out of order",
+			strings.Replace(sampleExtracted, "JANE DOE\nSan Francisco, CA\njane@example.com\n", "", 1) + "\nJANE DOE San Francisco, CA jane@example.com",
+			nil, map[string]string{"Jane Doe": "out of order", "San Francisco, CA": "out of order", "jane@example.com": "out of order"}},
+		{"words run together", strings.Replace(sampleExtracted, "Acme Corp", "AcmeCorp", 1), []string{"Snowflake"},
+			map[string]string{"Acme Corp": "run together", "Snowflake": "missing"}},
+		{"lost dates", strings.Replace(sampleExtracted, "2017 – 2020", "", 1), nil,
+			map[string]string{"2017 - 2020": "missing"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := checkATSText(tt.extracted, expected, tt.keywords, source)
+			if len(findings) != len(expected)+len(tt.keywords) {
+				t.Fatalf("got %d findings, want %d", len(findings), len(expected)+len(tt.keywords))
+			}
+			for _, f := range findings {
+				want := tt.want[f.Text]
+				if want == "" {
+					want = "ok"
+				}
+				if f.Status != want {
+					t.Errorf("%s %q: %s, want %s", f.Kind, f.Text, f.Status, want)
+				}
+			}
+		})
+	}
+}
+
+func TestStrongMatchKeywords(t *testing.T) {
+	got := strongMatchKeywords([]string{
+		"Kafka and Flink streaming experience",
+		"Strong SQL skills",
+		"mentoring engineers",
+		"Python data pipelines",
+		"Proven leadership of Snowflake projects",
+	})
+	want := "Kafka,Flink,SQL,mentoring,engineers,Python,Snowflake"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestATSWarnings(t *testing.T) {
+	warnings := atsWarnings("Built ﬁle pipelines\nwith distrib-\nuted storage")
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "ﬁ") || !strings.Contains(warnings[1], "distrib-uted") {
+		t.Errorf("warnings = %q", warnings)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+type autoMatchResult struct {
+	job       ScanResult
+	status    string
+	score     int
+	outputDir string
+	pdf       bool
+	err       error
+}
+
+// autoMatchCandidates returns the top n jobs by score at or above minScore.
+func autoMatchCandidates(jobs []ScanResult, n, minScore int) []ScanResult {
+	ranked := make([]ScanResult, 0, len(jobs))
+	for _, j := range jobs {
+		if j.Score >= minScore && j.URL != "" {
+			ranked = append(ranked, j)
+		}
+	}
+	sortByScore(ranked)
+	if len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// candidateResumeHashes returns the hashes of every resume the match flow
+// could tailor from, including the experience bank, so previously matched
+// jobs can be skipped.
+func candidateResumeHashes(template string) []string {
+	paths := []string{template}
+	if template == "" {
+		templates, _ := loadTemplates()
+		paths = []string{resumePath}
+		if len(templates) > 0 {
+			paths = nil
+			for _, t := range templates {
+				paths = append(paths, t.Path)
+			}
+		}
+		if bank := findExperienceBank(); bank != "" {
+			paths = append(paths, bank)
+		}
+	}
+
+	var hashes []string
+	for _, p := range paths {
+		if data, err := os.ReadFile(p); err == nil {
+			hashes = append(hashes, contentHash(string(data)))
+		}
+	}
+	return hashes
+}
+
+// autoMatchSteps are the lookups and matching runAutoMatch performs per
+// job, split out so the worker loop can be exercised without a database or
+// network.
+type autoMatchSteps struct {
+	findRun func(jobURL string) (score int, outputDir string, found bool, err error)
+	match   func(j ScanResult) (*matchOutcome, error)
+}
+
+func runAutoMatch(jobs []ScanResult, n, minScore, workers int, template string) {
+	candidates := autoMatchCandidates(jobs, n, minScore)
+	if len(candidates) == 0 {
+		fmt.Fprintf(scanLog, "\nNo jobs scored %d or higher, nothing to match.\n", minScore)
+		return
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	if err := InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not init database, previously matched jobs won't be skipped: %v\n", err)
+	}
+	hashes := candidateResumeHashes(template)
+
+	steps := autoMatchSteps{
+		match: func(j ScanResult) (*matchOutcome, error) {
+			return matchJobURL(j.URL, j.Company, template, io.Discard)
+		},
+	}
+	if db != nil && len(hashes) > 0 {
+		steps.findRun = func(jobURL string) (int, string, bool, error) {
+			return FindMatchRun(jobURL, hashes)
+		}
+	}
+
+	fmt.Fprintf(scanLog, "\n%s Matching top %d jobs (%d at a time)...\n", color.CyanString("→"), len(candidates), workers)
+	results := autoMatchJobs(context.Background(), scanLog, candidates, workers, steps)
+	printAutoMatchResults(scanLog, results)
+}
+
+// autoMatchJobs matches each candidate with at most workers in flight,
+// skipping jobs findRun reports as already matched. A failed job is recorded
+// in its result and doesn't stop the others.
+func autoMatchJobs(ctx context.Context, w io.Writer, candidates []ScanResult, workers int, steps autoMatchSteps) []autoMatchResult {
+	results := make([]autoMatchResult, len(candidates))
+	var mu sync.Mutex
+
+	logf := func(format string, args ...interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(w, format, args...)
+	}
+
+	runPool(ctx, workers, len(candidates), func(i int) {
+		j := candidates[i]
+		label := fmt.Sprintf("[%d/%d] %s — %s", i+1, len(candidates), j.Company, j.Title)
+		r := autoMatchResult{job: j}
+
+		if steps.findRun != nil {
+			score, dir, found, err := steps.findRun(j.URL)
+			if err != nil {
+				logf("  %s %s: could not check previous runs: %v\n", color.YellowString("⚠"), label, err)
+			} else if found {
+				r.status, r.score, r.outputDir = "skipped", score, dir
+				_, statErr := os.Stat(filepath.Join(dir, "resume.pdf"))
+				r.pdf = statErr == nil
+				logf("  %s %s: already matched with this resume\n", color.HiBlackString("-"), label)
+				results[i] = r
+				return
+			}
+		}
+
+		logf("  %s %s\n", color.CyanString("→"), label)
+		outcome, err := steps.match(j)
+		if err != nil {
+			r.status, r.err = "failed", err
+			logf("  %s %s: %v\n", color.RedString("✗"), label, err)
+			results[i] = r
+			return
+		}
+
+		r.status, r.score, r.outputDir, r.pdf = "matched", outcome.Result.Score, outcome.OutputDir, outcome.PDF != ""
+		if n := len(outcome.Result.Drift); n > 0 {
+			r.status = "review"
+			logf("  %s %s: %d drift findings, see %s/report.txt\n", color.RedString("⚠"), label, n, outcome.OutputDir)
+		}
+		logf("  %s %s: %d/100\n", color.GreenString("✓"), label, r.score)
+		results[i] = r
+	})
+	return results
+}
+
+func printAutoMatchResults(w io.Writer, results []autoMatchResult) {
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%-6s %-8s %-20s %-30s %-4s %s\n", "Score", "Status", "Company", "Title", "PDF", "Output")
+	fmt.Fprintln(w, strings.Repeat("─", 120))
+	for _, r := range results {
+		score := "-"
+		if r.status != "failed" {
+			score = fmt.Sprintf("%d", r.score)
+		}
+		pdf := "no"
+		if r.pdf {
+			pdf = "yes"
+		}
+		output := r.outputDir
+		if r.err != nil {
+			output = r.err.Error()
+		}
+		fmt.Fprintf(w, "%-6s %-8s %-20s %-30s %-4s %s\n", score, r.status, truncate(r.job.Company, 20), truncate(r.job.Title, 30), pdf, output)
+	}
+}
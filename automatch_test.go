@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAutoMatchCandidates(t *testing.T) {
+	jobs := []ScanResult{
+		{Title: "A", URL: "a", Score: 70},
+		{Title: "B", URL: "b", Score: 92},
+		{Title: "C", URL: "", Score: 99},
+		{Title: "D", URL: "d", Score: 85},
+		{Title: "E", URL: "e", Score: 88},
+	}
+
+	got := autoMatchCandidates(jobs, 2, 80)
+	if len(got) != 2 || got[0].Title != "B" || got[1].Title != "E" {
+		t.Errorf("candidates = %+v", got)
+	}
+	if jobs[0].Title != "A" {
+		t.Error("autoMatchCandidates reordered the input")
+	}
+	if got := autoMatchCandidates(jobs, 5, 95); len(got) != 0 {
+		t.Errorf("expected no candidates above 95, got %+v", got)
+	}
+}
+
+func TestAutoMatchJobs(t *testing.T) {
+	candidates := []ScanResult{
+		{Company: "Acme", Title: "Backend Engineer", URL: "https://acme.example/1"},
+		{Company: "Globex", Title: "Platform Engineer", URL: "https://globex.example/2"},
+		{Company: "Initech", Title: "SRE", URL: "https://initech.example/3"},
+		{Company: "Umbrella", Title: "Data Engineer", URL: "https://umbrella.example/4"},
+	}
+
+	var matched int32
+	steps := autoMatchSteps{
+		findRun: func(jobURL string) (int, string, bool, error) {
+			switch jobURL {
+			case "https://acme.example/1":
+				return 81, "results/acme", true, nil
+			case "https://umbrella.example/4":
+				return 0, "", false, errors.New("database is locked")
+			}
+			return 0, "", false, nil
+		},
+		match: func(j ScanResult) (*matchOutcome, error) {
+			atomic.AddInt32(&matched, 1)
+			switch j.Company {
+			case "Globex":
+				return nil, errors.New("fetching job: 404")
+			case "Initech":
+				return &matchOutcome{Result: &MatchResult{Score: 77, Drift: []DriftFinding{{Kind: "new_number"}}}, OutputDir: "results/initech"}, nil
+			}
+			return &matchOutcome{Result: &MatchResult{Score: 90}, OutputDir: "results/" + j.Company, PDF: "resume.pdf"}, nil
+		},
+	}
+
+	results := autoMatchJobs(context.Background(), io.Discard, candidates, 2, steps)
+
+	tests := []struct {
+		company string
+		status  string
+		score   int
+		dir     string
+		pdf     bool
+		err     bool
+	}{
+		{"Acme", "skipped", 81, "results/acme", false, false},
+		{"Globex", "failed", 0, "", false, true},
+		{"Initech", "review", 77, "results/initech", false, false},
+		{"Umbrella", "matched", 90, "results/Umbrella", true, false},
+	}
+	for i, tt := range tests {
+		r := results[i]
+		if r.job.Company != tt.company || r.status != tt.status || r.score != tt.score || r.outputDir != tt.dir || r.pdf != tt.pdf || (r.err != nil) != tt.err {
+			t.Errorf("result %d = %+v, want %+v", i, r, tt)
+		}
+	}
+	if matched != 3 {
+		t.Errorf("match called %d times, want 3 (the already matched job is skipped)", matched)
+	}
+}
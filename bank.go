@@ -0,0 +1,249 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// An experience bank is a resume.cls document listing every role with all
+// of its bullets, each optionally tagged:
+//
+//	\item Built a streaming platform on Kafka % resumectl:tags=streaming,data-platform
+//
+// or with "% resumectl:tags=..." on its own line above the bullet. The
+// assembler keeps the bullets most relevant to a job, so one file replaces
+// the resume.template.*.tex variants.
+const (
+	bankFile = "experience.bank.tex"
+	tagsMark = "resumectl:tags="
+
+	defaultBankBullets = 14
+
+	// assembledRetention is how long assembled resumes are kept for
+	// 'resumectl diff' before they are pruned.
+	assembledRetention = 90 * 24 * time.Hour
+)
+
+var bankBullets int
+
+var tagCommentRe = regexp.MustCompile(`\s*%\s*resumectl:tags=\S*`)
+
+// findExperienceBank returns the bank in the working directory or
+// ~/.resumectl, or "" when there is none.
+func findExperienceBank() string {
+	if _, err := os.Stat(bankFile); err == nil {
+		return bankFile
+	}
+	home, _ := os.UserHomeDir()
+	path := filepath.Join(home, ".resumectl", bankFile)
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	return ""
+}
+
+func bulletTags(b *Bullet) []string {
+	var tags []string
+	for _, l := range append(append([]string(nil), b.lead...), b.raw...) {
+		if a := annotation(l); strings.HasPrefix(a, tagsMark) {
+			for _, t := range strings.Split(strings.TrimPrefix(a, tagsMark), ",") {
+				if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+					tags = append(tags, t)
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// bankRelevance scores a bullet against the job: one point per content word
+// shared with the description, two per tag the description mentions.
+func bankRelevance(b *Bullet, jobWords map[string]bool, jobText string) int {
+	score := 0
+	for w := range contentWords(latexPlain(b.Text)) {
+		if jobWords[w] {
+			score++
+		}
+	}
+	for _, t := range bulletTags(b) {
+		if jobWords[t] || strings.Contains(jobText, strings.ReplaceAll(t, "-", " ")) {
+			score += 2
+		}
+	}
+	return score
+}
+
+// assembleResume builds a resume from the bank for job, keeping at most
+// budget bullets. Every list keeps its best bullet, pinned and locked
+// bullets always stay, and the rest of the budget goes to the most relevant
+// bullets (earlier roles win ties). Kept bullets are ordered most relevant
+// first, and skill items the job mentions move to the front of their row.
+// It returns the LaTeX and a line per list describing what was kept.
+func assembleResume(bank string, job *JobInfo, budget int) (string, []string, error) {
+	doc, err := parseResume(bank)
+	if err != nil {
+		return "", nil, fmt.Errorf("parsing bank: %v", err)
+	}
+	jobText := strings.ToLower(job.Title + "\n" + latexPlain(job.Description))
+	jobWords := contentWords(jobText)
+	locked := lockedLines(bank)
+
+	type candidate struct {
+		list, index, score int
+		bullet             *Bullet
+	}
+	lists := doc.Lists()
+	keep := make(map[*Bullet]bool)
+	scores := make(map[*Bullet]int)
+	var rest []candidate
+	for li, l := range lists {
+		best := -1
+		for i, b := range l.Bullets {
+			scores[b] = bankRelevance(b, jobWords, jobText)
+			if !removable(b, locked) {
+				keep[b] = true
+			}
+			if best < 0 || scores[b] > scores[l.Bullets[best]] {
+				best = i
+			}
+		}
+		if best >= 0 {
+			keep[l.Bullets[best]] = true
+		}
+		for i, b := range l.Bullets {
+			if !keep[b] {
+				rest = append(rest, candidate{li, i, scores[b], b})
+			}
+		}
+	}
+	sort.SliceStable(rest, func(i, j int) bool {
+		if rest[i].score != rest[j].score {
+			return rest[i].score > rest[j].score
+		}
+		return rest[i].list < rest[j].list
+	})
+	for _, c := range rest {
+		if len(keep) >= budget {
+			break
+		}
+		keep[c.bullet] = true
+	}
+
+	var notes []string
+	for i, l := range lists {
+		total := len(l.Bullets)
+		var kept []*Bullet
+		for _, b := range l.Bullets {
+			if keep[b] {
+				stripTags(b)
+				kept = append(kept, b)
+			}
+		}
+		sort.SliceStable(kept, func(a, b int) bool {
+			if isPinned(kept[a]) != isPinned(kept[b]) {
+				return isPinned(kept[a])
+			}
+			return scores[kept[a]] > scores[kept[b]]
+		})
+		l.Bullets = kept
+		if total > 0 {
+			name := fmt.Sprintf("list %d", i+1)
+			if role := roleForList(doc, l); role != nil {
+				name = latexPlain(role.Employer)
+			}
+			notes = append(notes, fmt.Sprintf("%s: %d of %d bullets", name, len(kept), total))
+		}
+	}
+
+	for _, r := range doc.SkillRows() {
+		var hits, misses []string
+		for _, item := range r.Items {
+			if strings.Contains(jobText, strings.ToLower(latexPlain(item))) {
+				hits = append(hits, item)
+			} else {
+				misses = append(misses, item)
+			}
+		}
+		r.Items = append(hits, misses...)
+	}
+	return doc.Render(), notes, nil
+}
+
+// stripTags removes tag annotations from a bullet so the assembled resume
+// reads like a hand-written template.
+func stripTags(b *Bullet) {
+	var lead []string
+	for _, l := range b.lead {
+		if isCommentLine(l) && strings.HasPrefix(annotation(l), tagsMark) {
+			continue
+		}
+		lead = append(lead, l)
+	}
+	b.lead = lead
+	for i, l := range b.raw {
+		b.raw[i] = tagCommentRe.ReplaceAllString(l, "")
+	}
+	b.comment = strings.TrimSpace(tagCommentRe.ReplaceAllString(b.comment, ""))
+}
+
+// assembleFromBank assembles a resume for job from the bank at path and
+// saves it under ~/.resumectl/assembled, named by its content hash so
+// concurrent matches don't collide and 'resumectl diff' can find it again.
+// It also returns the bank's hash, which match runs record so auto-match
+// can tell a job was already matched from this bank.
+func assembleFromBank(path string, job *JobInfo, w io.Writer) (string, string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", err
+	}
+	budget := bankBullets
+	if budget <= 0 {
+		budget = defaultBankBullets
+	}
+	latex, notes, err := assembleResume(string(src), job, budget)
+	if err != nil {
+		return "", "", err
+	}
+
+	fmt.Fprintf(w, "\n%s Assembling resume from %s\n", color.CyanString("→"), path)
+	for _, n := range notes {
+		fmt.Fprintf(w, "  %s\n", n)
+	}
+
+	out := assembledPath(contentHash(latex))
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(out, []byte(latex), 0644); err != nil {
+		return "", "", err
+	}
+	pruneAssembled(filepath.Dir(out), time.Now().Add(-assembledRetention))
+	return out, contentHash(string(src)), nil
+}
+
+// pruneAssembled removes assembled resumes last written before cutoff.
+func pruneAssembled(dir string, cutoff time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err == nil && info.Mode().IsRegular() && info.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+func assembledPath(hash string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".resumectl", "assembled", hash+".tex")
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var sampleBank = strings.NewReplacer(
+	"  % resumectl:pin-top\n", "  \\item Ran the on-call rotation for the data platform % resumectl:tags=reliability,on-call\n  % resumectl:pin-top\n",
+	"\\item Cut nightly batch runtime by 60\\%\n", "\\item Cut nightly batch runtime by 60\\%\n% resumectl:tags=cost\n\\item Trimmed the Snowflake bill by a third\n",
+).Replace(sampleResume)
+
+func TestAssembleResume(t *testing.T) {
+	job := &JobInfo{
+		Title:       "Senior Data Engineer",
+		Description: "You will own our Kafka streaming platform and keep Snowflake cost under control. Reliability matters. Go or Scala.",
+	}
+	tests := []struct {
+		name   string
+		budget int
+		keep   []string
+		drop   []string
+	}{
+		{"minimum", 1,
+			[]string{"Mentored 4 engineers", "Ran the on-call rotation", "Trimmed the Snowflake bill", "resumectl: self-custodial"},
+			[]string{"Built a streaming platform", "Led migration", "Designed the warehouse", "Cut nightly batch"}},
+		{"budget fills by relevance", 5,
+			[]string{"Mentored 4 engineers", "Built a streaming platform", "Trimmed the Snowflake bill", "Ran the on-call rotation"},
+			[]string{"Led migration", "Designed the warehouse", "Cut nightly batch"}},
+		{"everything", 20,
+			[]string{"Ran the on-call rotation", "Led migration", "Designed the warehouse", "Cut nightly batch"},
+			nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			latex, notes, err := assembleResume(sampleBank, job, tt.budget)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range tt.keep {
+				if !strings.Contains(latex, s) {
+					t.Errorf("missing %q", s)
+				}
+			}
+			for _, s := range tt.drop {
+				if strings.Contains(latex, s) {
+					t.Errorf("kept %q", s)
+				}
+			}
+			if strings.Contains(latex, tagsMark) {
+				t.Errorf("tags left in output:\n%s", latex)
+			}
+			if len(notes) != 3 {
+				t.Errorf("notes = %q", notes)
+			}
+		})
+	}
+}
+
+func TestAssembleResumeOrder(t *testing.T) {
+	job := &JobInfo{Title: "Data Engineer", Description: "Snowflake cost, Kafka, Spark and SQL."}
+	latex, _, err := assembleResume(sampleBank, job, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := parseResume(latex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lists := doc.Lists()
+	if got := lists[0].Bullets[0].Text; got != `Mentored 4 engineers` {
+		t.Errorf("pinned bullet not first: %q", got)
+	}
+	if got := lists[1].Bullets[0].Text; !strings.HasPrefix(got, "Trimmed the Snowflake bill") {
+		t.Errorf("most relevant bullet not first: %q", got)
+	}
+	if got := doc.SkillRows()[1].Items; strings.Join(got[:2], ",") != "Kafka,Spark" {
+		t.Errorf("skill row = %q", got)
+	}
+}
+
+func TestAssembleFromBank(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	bank := filepath.Join(os.Getenv("HOME"), ".resumectl", bankFile)
+	os.MkdirAll(filepath.Dir(bank), 0755)
+	if err := os.WriteFile(bank, []byte(sampleBank), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := assembledPath("000000000000")
+	os.MkdirAll(filepath.Dir(stale), 0755)
+	os.WriteFile(stale, []byte("old"), 0644)
+	old := time.Now().Add(-assembledRetention - time.Hour)
+	os.Chtimes(stale, old, old)
+
+	job := &JobInfo{Title: "Data Engineer", Description: "Kafka streaming and on-call reliability"}
+	path, bankHash, err := assembleFromBank(bank, job, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bankHash != contentHash(sampleBank) {
+		t.Errorf("bank hash = %s, want %s", bankHash, contentHash(sampleBank))
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("assembled resume not written: %v", err)
+	}
+	if _, err := os.Stat(stale); err == nil {
+		t.Error("stale assembled resume not pruned")
+	}
+
+	if hashes := candidateResumeHashes(""); !slices.Contains(hashes, bankHash) {
+		t.Errorf("candidate hashes %v don't include the bank", hashes)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Compiler turns a .tex file into a PDF next to it and returns the
+// engine's output.
+type Compiler interface {
+	Compile(ctx context.Context, dir, texFile string) (log string, err error)
+}
+
+// latexEngines are the supported engines and their command lines; the .tex
+// file name is appended. TeX Live engines run without shell escape.
+var latexEngines = map[string][]string{
+	"tectonic": {"tectonic"},
+	"latexmk":  {"latexmk", "-pdf", "-interaction=nonstopmode", "-halt-on-error", "-file-line-error", "-no-shell-escape"},
+	"pdflatex": {"pdflatex", "-interaction=nonstopmode", "-halt-on-error", "-file-line-error", "-no-shell-escape"},
+	"xelatex":  {"xelatex", "-interaction=nonstopmode", "-halt-on-error", "-file-line-error", "-no-shell-escape"},
+}
+
+const defaultCompileTimeout = 2 * time.Minute
+
+// engineCompiler runs a LaTeX engine in a throwaway copy of the source
+// directory, so nothing but the PDF lands in the results folder.
+type engineCompiler struct {
+	name    string
+	command []string
+	timeout time.Duration
+	epoch   string
+}
+
+// newCompiler builds the compiler configured by the environment:
+//
+//	RESUMECTL_LATEX_ENGINE   tectonic (default), latexmk, pdflatex, xelatex
+//	RESUMECTL_LATEX_TIMEOUT  per-compile limit, e.g. 90s (default 2m)
+//	SOURCE_DATE_EPOCH        timestamp embedded in the PDF (default: start of today, UTC)
+func newCompiler() (Compiler, error) {
+	name := os.Getenv("RESUMECTL_LATEX_ENGINE")
+	if name == "" {
+		name = "tectonic"
+	}
+	command, ok := latexEngines[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown LaTeX engine %q (available: tectonic, latexmk, pdflatex, xelatex)", name)
+	}
+
+	timeout := defaultCompileTimeout
+	if v := os.Getenv("RESUMECTL_LATEX_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid RESUMECTL_LATEX_TIMEOUT %q", v)
+		}
+		timeout = d
+	}
+
+	epoch := os.Getenv("SOURCE_DATE_EPOCH")
+	if epoch == "" {
+		epoch = fmt.Sprint(time.Now().UTC().Truncate(24 * time.Hour).Unix())
+	}
+	return &engineCompiler{name: name, command: command, timeout: timeout, epoch: epoch}, nil
+}
+
+func (c *engineCompiler) Compile(ctx context.Context, dir, texFile string) (string, error) {
+	work, err := os.MkdirTemp("", "resumectl-compile-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(work)
+
+	pdfName := strings.TrimSuffix(texFile, filepath.Ext(texFile)) + ".pdf"
+	if err := stageCompileInputs(dir, work, pdfName); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var log bytes.Buffer
+	cmd := exec.CommandContext(ctx, c.command[0], append(c.command[1:], texFile)...)
+	cmd.Dir = work
+	cmd.Stdout = &log
+	cmd.Stderr = &log
+	cmd.Env = append(os.Environ(), "SOURCE_DATE_EPOCH="+c.epoch, "FORCE_SOURCE_DATE=1")
+	cmd.WaitDelay = time.Second
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return log.String(), fmt.Errorf("%s timed out after %s", c.name, c.timeout)
+		}
+		return log.String(), fmt.Errorf("%s: %w", c.name, err)
+	}
+
+	pdf, err := os.ReadFile(filepath.Join(work, pdfName))
+	if err != nil {
+		return log.String(), fmt.Errorf("%s produced no PDF", c.name)
+	}
+	return log.String(), os.WriteFile(filepath.Join(dir, pdfName), pdf, 0644)
+}
+
+// stageCompileInputs copies the tree under dir (except a previous PDF) and
+// the resume class files into work, so \input and \includegraphics paths
+// into subdirectories still resolve. Class files come from the working
+// directory, or ~/.resumectl when there are none.
+func stageCompileInputs(dir, work, pdfName string) error {
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(filepath.Join(work, rel), 0755)
+		case !d.Type().IsRegular() || rel == pdfName:
+			return nil
+		}
+		return copyFile(path, filepath.Join(work, rel))
+	})
+	if err != nil {
+		return err
+	}
+
+	clsFiles, _ := filepath.Glob("*.cls")
+	if len(clsFiles) == 0 {
+		home, _ := os.UserHomeDir()
+		clsFiles, _ = filepath.Glob(filepath.Join(home, ".resumectl", "*.cls"))
+	}
+	for _, f := range clsFiles {
+		if err := copyFile(f, filepath.Join(work, filepath.Base(f))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func copyFile(from, to string) error {
+	src, err := os.ReadFile(from)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(to, src, 0644)
+}
+
+// compileResume compiles texFile in dir with the configured engine and
+// returns its output.
+func compileResume(dir, texFile string) (string, error) {
+	c, err := newCompiler()
+	if err != nil {
+		return "", err
+	}
+	return c.Compile(context.Background(), dir, texFile)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEngineCompiler(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		timeout time.Duration
+		wantPDF string
+		wantErr string
+	}{
+		{"writes the PDF back", `cat "$1" > "${1%.tex}.pdf"; echo "epoch $SOURCE_DATE_EPOCH" >> "${1%.tex}.pdf"; touch stray.aux`, time.Minute, "\\documentclass{resume}epoch 1700000000\n", ""},
+		{"sees the class file", `test -f resume.cls && echo ok > "${1%.tex}.pdf"`, time.Minute, "ok\n", ""},
+		{"failure keeps the log", `echo "error: resume.tex:3: Undefined control sequence"; exit 1`, time.Minute, "", "fake"},
+		{"no PDF", `true`, time.Minute, "", "produced no PDF"},
+		{"timeout", `sleep 5`, 50 * time.Millisecond, "", "timed out"},
+	}
+
+	cwd, _ := os.Getwd()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			os.WriteFile(filepath.Join(dir, "resume.tex"), []byte(`\documentclass{resume}`), 0644)
+			c := &engineCompiler{name: "fake", command: []string{"sh", "-c", tt.script, "sh"}, timeout: tt.timeout, epoch: "1700000000"}
+
+			log, err := c.Compile(context.Background(), dir, "resume.tex")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				if tt.name == "failure keeps the log" && len(parseLatexLog(log)) != 1 {
+					t.Errorf("log = %q", log)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			pdf, _ := os.ReadFile(filepath.Join(dir, "resume.pdf"))
+			if string(pdf) != tt.wantPDF {
+				t.Errorf("pdf = %q, want %q", pdf, tt.wantPDF)
+			}
+			entries, _ := os.ReadDir(dir)
+			if len(entries) != 2 {
+				t.Errorf("results dir has %d files, want resume.tex and resume.pdf", len(entries))
+			}
+			if wd, _ := os.Getwd(); wd != cwd {
+				t.Errorf("working directory changed to %s", wd)
+			}
+		})
+	}
+}
+
+func TestEngineCompilerMissingEngine(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "resume.tex"), nil, 0644)
+	c := &engineCompiler{name: "nope", command: []string{"resumectl-no-such-engine"}, timeout: time.Minute}
+	if _, err := c.Compile(context.Background(), dir, "resume.tex"); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("err = %v, want exec.ErrNotFound", err)
+	}
+}
+
+func TestNewCompiler(t *testing.T) {
+	tests := []struct {
+		engine, timeout string
+		want            string
+		err             bool
+	}{
+		{"", "", "tectonic", false},
+		{"pdflatex", "90s", "pdflatex", false},
+		{"latexmk", "", "latexmk", false},
+		{"lualatex", "", "", true},
+		{"xelatex", "soon", "", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("RESUMECTL_LATEX_ENGINE", tt.engine)
+		t.Setenv("RESUMECTL_LATEX_TIMEOUT", tt.timeout)
+		c, err := newCompiler()
+		if (err != nil) != tt.err {
+			t.Errorf("%q/%q: err = %v", tt.engine, tt.timeout, err)
+			continue
+		}
+		if err == nil && c.(*engineCompiler).command[0] != tt.want {
+			t.Errorf("%q: engine %s, want %s", tt.engine, c.(*engineCompiler).command[0], tt.want)
+		}
+	}
+}
+
+func TestStageCompileInputs(t *testing.T) {
+	dir, work := t.TempDir(), t.TempDir()
+	os.MkdirAll(filepath.Join(dir, "sections", "img"), 0755)
+	os.WriteFile(filepath.Join(dir, "resume.tex"), []byte(`\input{sections/work}`), 0644)
+	os.WriteFile(filepath.Join(dir, "resume.pdf"), []byte("old"), 0644)
+	os.WriteFile(filepath.Join(dir, "sections", "work.tex"), []byte("work"), 0644)
+	os.WriteFile(filepath.Join(dir, "sections", "img", "logo.png"), []byte("png"), 0644)
+
+	if err := stageCompileInputs(dir, work, "resume.pdf"); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"resume.tex", "sections/work.tex", "sections/img/logo.png"} {
+		if _, err := os.Stat(filepath.Join(work, f)); err != nil {
+			t.Errorf("%s not staged: %v", f, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(work, "resume.pdf")); err == nil {
+		t.Error("previous PDF was staged")
+	}
+}
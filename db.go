@@ -12,7 +12,7 @@ import (
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 //go:embed migrations/*.sql
@@ -27,6 +27,10 @@ type Job struct {
 	Title     string
 	Score     int
 	Status    string
+	Source    string
+	Location  string
+	Salary    string
+	Pay       SalaryRange
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -58,59 +62,341 @@ func InitDB() error {
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
 		return err
 	}
-	return nil
+	return backfillJobKeys()
 }
 
-func SaveJob(url, company, title string, score int) error {
-	_, err := db.Exec(`
-		INSERT INTO jobs (url, company, title, score, status)
-		VALUES ($1, $2, $3, $4, 'new')
+func SaveJob(url, company, title, description string, score int) error {
+	fp := simhash(description)
+	id, err := findDuplicateJob(url, company, title, "", fp)
+	if err != nil {
+		return err
+	}
+	if id != 0 {
+		_, err = db.Exec(`
+			UPDATE jobs SET
+				score = $1,
+				status = CASE WHEN status = 'discovered' THEN 'new' ELSE status END,
+				updated_at = NOW()
+			WHERE id = $2
+		`, score, id)
+		if err != nil {
+			return err
+		}
+		return addJobURL(id, url, "")
+	}
+
+	_, err = db.Exec(`
+		INSERT INTO jobs (url, company, title, score, status, company_key, title_key, fingerprint)
+		VALUES ($1, $2, $3, $4, 'new', $5, $6, NULLIF($7, 0))
 		ON CONFLICT(url) DO UPDATE SET
 			score = EXCLUDED.score,
+			status = CASE WHEN jobs.status = 'discovered' THEN 'new' ELSE jobs.status END,
 			updated_at = NOW()
-	`, url, company, title, score)
+	`, url, company, title, score, normalizeCompanyName(company), normalizeJobTitle(title), int64(fp))
+	return err
+}
+
+func SaveDiscoveredJob(r ScanResult) error {
+	fp := simhash(r.Description)
+	id, err := findDuplicateJob(r.URL, r.Company, r.Title, r.Location, fp)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		err = db.QueryRow(`
+			INSERT INTO jobs (url, company, title, score, status, source, location, salary, posted_at, company_key, title_key, fingerprint)
+			VALUES ($1, $2, $3, $4, 'discovered', $5, $6, $7, NOW() - make_interval(days => $8), $9, $10, NULLIF($11, 0))
+			RETURNING id
+		`, r.URL, r.Company, r.Title, r.Score, r.Source, r.Location, r.Salary, r.AgeDays,
+			normalizeCompanyName(r.Company), normalizeJobTitle(r.Title), int64(fp)).Scan(&id)
+	} else {
+		_, err = db.Exec(`
+			UPDATE jobs SET
+				score = CASE WHEN status = 'discovered' THEN $1 ELSE score END,
+				source = COALESCE(source, $2),
+				location = $3,
+				salary = $4,
+				posted_at = COALESCE(posted_at, NOW() - make_interval(days => $5)),
+				fingerprint = COALESCE(fingerprint, NULLIF($6, 0)),
+				updated_at = NOW()
+			WHERE id = $7
+		`, r.Score, r.Source, r.Location, r.Salary, r.AgeDays, int64(fp), id)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, u := range append([]string{r.URL}, r.AltURLs...) {
+		if err := addJobURL(id, u, r.Source); err != nil {
+			return err
+		}
+	}
+	return SaveJobSalary(r.URL, r.Pay)
+}
+
+// duplicateWindowDays bounds how far back a posting from another board is
+// merged into a stored job. Older rows are likely a different opening.
+const duplicateWindowDays = 60
+
+// findDuplicateJob returns the ID of a stored job that is the same posting,
+// either by URL or, like postingKey.duplicates, by normalized company plus a
+// description fingerprint or title and location. Only recent jobs that
+// haven't been acted on are merged into, so a new opening doesn't update an
+// old application.
+func findDuplicateJob(url, company, title, location string, fp uint64) (int64, error) {
+	id, err := jobIDForURL(url)
+	if err == nil {
+		return id, nil
+	}
+	if err != sql.ErrNoRows {
+		return 0, err
+	}
+
+	companyKey := normalizeCompanyName(company)
+	if companyKey == "" || companyKey == "unknown" {
+		return 0, nil
+	}
+	titleKey := normalizeJobTitle(title)
+
+	rows, err := db.Query(`
+		SELECT id, COALESCE(title_key, ''), COALESCE(location, ''), COALESCE(fingerprint, 0) FROM jobs
+		WHERE company_key = $1 AND status IN ('discovered', 'new')
+			AND updated_at > NOW() - make_interval(days => $2)
+		ORDER BY created_at`, companyKey, duplicateWindowDays)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var candidate, storedFP int64
+		var storedTitle, storedLocation string
+		if err := rows.Scan(&candidate, &storedTitle, &storedLocation, &storedFP); err != nil {
+			return 0, err
+		}
+		if sameFingerprint(uint64(storedFP), fp) || (storedTitle == titleKey && sameLocation(storedLocation, location)) {
+			return candidate, nil
+		}
+	}
+	return 0, rows.Err()
+}
+
+func jobIDForURL(url string) (int64, error) {
+	var id int64
+	err := db.QueryRow(`
+		SELECT id FROM jobs WHERE url = $1
+		UNION ALL
+		SELECT job_id FROM job_urls WHERE url = $1
+		LIMIT 1`, url).Scan(&id)
+	return id, err
+}
+
+func addJobURL(jobID int64, url, source string) error {
+	if url == "" {
+		return nil
+	}
+	_, err := db.Exec(`
+		INSERT INTO job_urls (job_id, url, source)
+		SELECT $1, $2, NULLIF($3, '')
+		WHERE NOT EXISTS (SELECT 1 FROM jobs WHERE url = $2)
+		ON CONFLICT (url) DO NOTHING
+	`, jobID, url, source)
+	return err
+}
+
+func backfillJobKeys() error {
+	rows, err := db.Query(`SELECT id, COALESCE(company, ''), COALESCE(title, '') FROM jobs WHERE company_key IS NULL`)
+	if err != nil {
+		return err
+	}
+	type key struct {
+		id             int64
+		company, title string
+	}
+	var pending []key
+	for rows.Next() {
+		var k key
+		if err := rows.Scan(&k.id, &k.company, &k.title); err != nil {
+			rows.Close()
+			return err
+		}
+		pending = append(pending, k)
+	}
+	rows.Close()
+
+	for _, k := range pending {
+		if _, err := db.Exec(`UPDATE jobs SET company_key=$1, title_key=$2 WHERE id=$3`,
+			normalizeCompanyName(k.company), normalizeJobTitle(k.title), k.id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func SaveJobSalary(url string, pay SalaryRange) error {
+	if pay.IsZero() {
+		return nil
+	}
+	id, err := jobIDForURL(url)
+	if err != nil {
+		return err
+	}
+	min, max := pay.Annual()
+	_, err = db.Exec(`
+		UPDATE jobs SET salary_min=$1, salary_max=$2, salary_currency=$3, updated_at=NOW()
+		WHERE id=$4
+	`, int(min), int(max), pay.Currency, id)
 	return err
 }
 
-func SaveMatchRun(jobURL string, score int, strongMatches, gaps []string, sourceHash, tailoredHash, outputDir string) error {
-	var jobID int64
-	err := db.QueryRow("SELECT id FROM jobs WHERE url = $1", jobURL).Scan(&jobID)
+// matchRun is one tailoring run as stored in match_runs. Template is the
+// resume it was tailored from; the runner-up and margin are only known when
+// Ranking holds several templates.
+type matchRun struct {
+	JobURL        string
+	Score         int
+	StrongMatches []string
+	Gaps          []string
+	SourceHash    string
+	BankHash      string
+	TailoredHash  string
+	OutputDir     string
+	Review        []reviewDecision
+	Template      string
+	Ranking       []templateRank
+}
+
+func SaveMatchRun(run matchRun) error {
+	jobID, err := jobIDForURL(run.JobURL)
 	if err != nil {
 		return err
 	}
 
-	matchesJSON, _ := json.Marshal(strongMatches)
-	gapsJSON, _ := json.Marshal(gaps)
+	matchesJSON, _ := json.Marshal(run.StrongMatches)
+	gapsJSON, _ := json.Marshal(run.Gaps)
+	var reviewJSON sql.NullString
+	if run.Review != nil {
+		b, _ := json.Marshal(run.Review)
+		reviewJSON = sql.NullString{String: string(b), Valid: true}
+	}
+	var runnerUp sql.NullString
+	var margin sql.NullInt64
+	if len(run.Ranking) > 1 {
+		runnerUp = sql.NullString{String: run.Ranking[1].Path, Valid: true}
+		margin = sql.NullInt64{Int64: int64(run.Ranking[0].Margin), Valid: true}
+	}
 
 	_, err = db.Exec(`
-		INSERT INTO match_runs (job_id, score, strong_matches, gaps, source_resume_hash, tailored_resume_hash, output_dir)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-	`, jobID, score, string(matchesJSON), string(gapsJSON), sourceHash, tailoredHash, outputDir)
+		INSERT INTO match_runs (job_id, score, strong_matches, gaps, source_resume_hash, bank_hash, tailored_resume_hash, output_dir, review_decisions,
+			chosen_template, runner_up_template, template_margin)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, NULLIF($10, ''), $11, $12)
+	`, jobID, run.Score, string(matchesJSON), string(gapsJSON), run.SourceHash, run.BankHash, run.TailoredHash, run.OutputDir, reviewJSON, run.Template, runnerUp, margin)
 	return err
 }
 
+// FindMatchRun returns the latest match run for a job that was tailored from
+// one of the given source resume hashes, or assembled from a bank with one
+// of them.
+func FindMatchRun(jobURL string, sourceHashes []string) (score int, outputDir string, found bool, err error) {
+	jobID, err := jobIDForURL(jobURL)
+	if err == sql.ErrNoRows {
+		return 0, "", false, nil
+	}
+	if err != nil {
+		return 0, "", false, err
+	}
+
+	err = db.QueryRow(`
+		SELECT score, COALESCE(output_dir, '') FROM match_runs
+		WHERE job_id = $1 AND (source_resume_hash = ANY($2) OR bank_hash = ANY($2))
+		ORDER BY created_at DESC LIMIT 1
+	`, jobID, pq.Array(sourceHashes)).Scan(&score, &outputDir)
+	if err == sql.ErrNoRows {
+		return 0, "", false, nil
+	}
+	return score, outputDir, err == nil, err
+}
+
+// FindSourceHash returns the source resume hash of the latest match run
+// written to outputDir.
+func FindSourceHash(outputDir string) (string, error) {
+	var hash string
+	err := db.QueryRow(`
+		SELECT COALESCE(source_resume_hash, '') FROM match_runs
+		WHERE output_dir = $1
+		ORDER BY created_at DESC LIMIT 1`, outputDir).Scan(&hash)
+	return hash, err
+}
+
 func contentHash(content string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(content)))[:12]
 }
 
+const jobColumns = `id, url, company, title, COALESCE(score, 0), status,
+	COALESCE(source, ''), COALESCE(location, ''), COALESCE(salary, ''),
+	COALESCE(salary_min, 0), COALESCE(salary_max, 0), COALESCE(salary_currency, ''),
+	created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanJob(row rowScanner) (Job, error) {
+	var j Job
+	err := row.Scan(&j.ID, &j.URL, &j.Company, &j.Title, &j.Score, &j.Status, &j.Source, &j.Location, &j.Salary,
+		&j.Pay.Min, &j.Pay.Max, &j.Pay.Currency, &j.CreatedAt, &j.UpdatedAt)
+	if !j.Pay.IsZero() {
+		j.Pay.Period = "year"
+	}
+	return j, err
+}
+
 func FindJobByQuery(query string) (*Job, error) {
 	row := db.QueryRow(`
-		SELECT id, url, company, title, score, status, created_at, updated_at
+		SELECT `+jobColumns+`
 		FROM jobs
-		WHERE url = $1 OR LOWER(company) LIKE '%' || LOWER($2) || '%' OR LOWER(title) LIKE '%' || LOWER($3) || '%'
+		WHERE url = $1 OR id IN (SELECT job_id FROM job_urls WHERE url = $1) OR LOWER(company) LIKE '%' || LOWER($2) || '%' OR LOWER(title) LIKE '%' || LOWER($3) || '%'
 		ORDER BY created_at DESC LIMIT 1`, query, query, query)
-	var j Job
-	err := row.Scan(&j.ID, &j.URL, &j.Company, &j.Title, &j.Score, &j.Status, &j.CreatedAt, &j.UpdatedAt)
+	j, err := scanJob(row)
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("no job found matching %q", query)
 	}
 	return &j, err
 }
 
+func FindJobByID(id int) (*Job, error) {
+	row := db.QueryRow(`SELECT `+jobColumns+` FROM jobs WHERE id = $1`, id)
+	j, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("no job with id %d", id)
+	}
+	return &j, err
+}
+
+func RecentlyRejectedCompanies(months int) ([]string, error) {
+	rows, err := db.Query(`
+		SELECT DISTINCT company FROM jobs
+		WHERE status='rejected' AND COALESCE(rejected_at, updated_at) > NOW() - make_interval(months => $1)`, months)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var companies []string
+	for rows.Next() {
+		var c string
+		if err := rows.Scan(&c); err != nil {
+			return nil, err
+		}
+		companies = append(companies, c)
+	}
+	return companies, nil
+}
+
 func UpdateJobStatus(id int, status string) error {
-	validStatuses := map[string]bool{"new": true, "applied": true, "screening": true, "interview": true, "offer": true, "rejected": true, "withdrawn": true}
+	validStatuses := map[string]bool{"discovered": true, "new": true, "applied": true, "screening": true, "interview": true, "offer": true, "rejected": true, "withdrawn": true}
 	if !validStatuses[status] {
-		return fmt.Errorf("invalid status %q: must be one of new, applied, screening, interview, offer, rejected, withdrawn", status)
+		return fmt.Errorf("invalid status %q: must be one of discovered, new, applied, screening, interview, offer, rejected, withdrawn", status)
 	}
 	var err error
 	switch status {
@@ -124,8 +410,8 @@ func UpdateJobStatus(id int, status string) error {
 	return err
 }
 
-func ListJobs(status string, minScore int) ([]Job, error) {
-	query := "SELECT id, url, company, title, score, status, created_at, updated_at FROM jobs WHERE 1=1"
+func ListJobs(status string, minScore int, sortBy string) ([]Job, error) {
+	query := "SELECT " + jobColumns + " FROM jobs WHERE 1=1"
 	args := []interface{}{}
 	i := 1
 
@@ -138,7 +424,16 @@ func ListJobs(status string, minScore int) ([]Job, error) {
 		query += fmt.Sprintf(" AND score >= $%d", i)
 		args = append(args, minScore)
 	}
-	query += " ORDER BY created_at DESC"
+	switch sortBy {
+	case "salary":
+		// Amounts in different currencies aren't comparable: USD first, then
+		// each other currency in turn.
+		query += " ORDER BY salary_currency IS DISTINCT FROM 'USD', salary_currency NULLS LAST, salary_max DESC NULLS LAST, salary_min DESC NULLS LAST, created_at DESC"
+	case "score":
+		query += " ORDER BY score DESC NULLS LAST, created_at DESC"
+	default:
+		query += " ORDER BY created_at DESC"
+	}
 
 	rows, err := db.Query(query, args...)
 	if err != nil {
@@ -148,8 +443,8 @@ func ListJobs(status string, minScore int) ([]Job, error) {
 
 	var jobs []Job
 	for rows.Next() {
-		var j Job
-		if err := rows.Scan(&j.ID, &j.URL, &j.Company, &j.Title, &j.Score, &j.Status, &j.CreatedAt, &j.UpdatedAt); err != nil {
+		j, err := scanJob(rows)
+		if err != nil {
 			return nil, err
 		}
 		jobs = append(jobs, j)
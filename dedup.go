@@ -0,0 +1,185 @@
+package main
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+)
+
+const simhashThreshold = 3
+
+var titleAbbreviations = map[string]string{
+	"sr": "senior", "snr": "senior", "jr": "junior", "eng": "engineer", "engr": "engineer",
+	"mgr": "manager", "dev": "developer", "swe": "software engineer", "sde": "software engineer",
+	"ml": "machine learning", "ai": "artificial intelligence", "ops": "operations",
+}
+
+var (
+	titleNoise       = regexp.MustCompile(`(?i)\(.*?\)|\[.*?\]|\s[-–—|]\s*(remote|hybrid|onsite|on-site|us|usa|united states|emea|anywhere).*$`)
+	titleNonWordRe   = regexp.MustCompile(`[^a-z0-9+# ]+`)
+	simhashNonWordRe = regexp.MustCompile(`[^a-zA-Z0-9 ]+`)
+)
+
+func normalizeJobTitle(title string) string {
+	title = titleNoise.ReplaceAllString(title, " ")
+	title = strings.ToLower(title)
+	title = titleNonWordRe.ReplaceAllString(title, " ")
+
+	var words []string
+	for _, w := range strings.Fields(title) {
+		if full, ok := titleAbbreviations[w]; ok {
+			w = full
+		}
+		words = append(words, w)
+	}
+	return strings.Join(words, " ")
+}
+
+func simhash(text string) uint64 {
+	words := strings.Fields(strings.ToLower(simhashNonWordRe.ReplaceAllString(text, " ")))
+	if len(words) < 3 {
+		return 0
+	}
+
+	var weights [64]int
+	for i := 0; i+3 <= len(words); i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:i+3], " ")))
+		sum := h.Sum64()
+		for b := 0; b < 64; b++ {
+			if sum&(1<<uint(b)) != 0 {
+				weights[b]++
+			} else {
+				weights[b]--
+			}
+		}
+	}
+
+	var fp uint64
+	for b := 0; b < 64; b++ {
+		if weights[b] > 0 {
+			fp |= 1 << uint(b)
+		}
+	}
+	return fp
+}
+
+func sameFingerprint(a, b uint64) bool {
+	return a != 0 && b != 0 && bits.OnesCount64(a^b) <= simhashThreshold
+}
+
+// locationKey reduces a location to the place it names, so "Austin, TX"
+// and "Austin, Texas" compare equal. It is empty when nothing is known.
+func locationKey(text string) string {
+	loc := parseLocation(text)
+	switch {
+	case loc.City != "":
+		return strings.ToLower(loc.City + "," + loc.Region + "," + loc.Country)
+	case loc.Region != "":
+		return strings.ToLower(loc.Region + "," + loc.Country)
+	case loc.Remote:
+		return "remote," + strings.ToLower(strings.Join(loc.RemoteScope, ","))
+	}
+	return strings.ToLower(strings.TrimSpace(text))
+}
+
+func sameLocation(a, b string) bool {
+	keyA := locationKey(a)
+	return keyA != "" && keyA == locationKey(b)
+}
+
+// postingKey holds the normalized fields postings are compared on, so a
+// batch can be deduplicated without re-normalizing each job per pair.
+type postingKey struct {
+	company     string
+	title       string
+	location    string
+	fingerprint uint64
+}
+
+func newPostingKey(j ScanResult) postingKey {
+	return postingKey{
+		company:     normalizeCompanyName(j.Company),
+		title:       normalizeJobTitle(j.Title),
+		location:    locationKey(j.Location),
+		fingerprint: simhash(j.Description),
+	}
+}
+
+// duplicates reports whether two postings are the same opening: same
+// company, and either near-identical descriptions or the same title in the
+// same place. A title alone isn't enough, since companies hire for one role
+// in several offices.
+func (k postingKey) duplicates(other postingKey) bool {
+	if k.company == "" || k.company == "unknown" || k.company != other.company {
+		return false
+	}
+	if sameFingerprint(k.fingerprint, other.fingerprint) {
+		return true
+	}
+	return k.title == other.title && k.location != "" && k.location == other.location
+}
+
+func mergeDuplicates(jobs []ScanResult) ([]ScanResult, int) {
+	var merged []ScanResult
+	var keys []postingKey
+	dupes := 0
+
+	for _, j := range jobs {
+		key := newPostingKey(j)
+		found := false
+		for i := range merged {
+			if !keys[i].duplicates(key) {
+				continue
+			}
+			before := merged[i]
+			merged[i] = mergePosting(merged[i], j)
+			if merged[i].Description != before.Description || merged[i].Location != before.Location {
+				keys[i] = newPostingKey(merged[i])
+			}
+			dupes++
+			found = true
+			break
+		}
+		if !found {
+			merged = append(merged, j)
+			keys = append(keys, key)
+		}
+	}
+
+	return merged, dupes
+}
+
+func mergePosting(into, other ScanResult) ScanResult {
+	if other.URL != "" && other.URL != into.URL && !containsString(into.AltURLs, other.URL) {
+		into.AltURLs = append(into.AltURLs, other.URL)
+	}
+	for _, u := range other.AltURLs {
+		if u != into.URL && !containsString(into.AltURLs, u) {
+			into.AltURLs = append(into.AltURLs, u)
+		}
+	}
+	for _, s := range strings.Split(other.Source, ",") {
+		if s != "" && !containsString(strings.Split(into.Source, ","), s) {
+			into.Source += "," + s
+		}
+	}
+
+	if len(other.Description) > len(into.Description) {
+		into.Description = other.Description
+	}
+	if into.Pay.IsZero() && !other.Pay.IsZero() {
+		into.Pay = other.Pay
+	}
+	if into.Salary == "" {
+		into.Salary = other.Salary
+	}
+	if into.Location == "" {
+		into.Location = other.Location
+	}
+	if other.AgeDays > into.AgeDays {
+		into.Age, into.AgeDays = other.Age, other.AgeDays
+	}
+	return into
+}
@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+func TestNormalizeJobTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		want  string
+	}{
+		{"Sr. Software Eng", "senior software engineer"},
+		{"Senior Software Engineer (Remote)", "senior software engineer"},
+		{"Senior Software Engineer - Remote, US", "senior software engineer"},
+		{"SWE II", "software engineer ii"},
+	}
+	for _, tt := range tests {
+		if got := normalizeJobTitle(tt.title); got != tt.want {
+			t.Errorf("normalizeJobTitle(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+}
+
+func TestMergeDuplicates(t *testing.T) {
+	desc := "We are hiring a backend engineer to build payment systems in Go and Postgres for our growing team"
+	jobs := []ScanResult{
+		{Title: "Sr. Backend Engineer", Company: "Acme Inc", Location: "Austin, TX", URL: "https://a/1", Source: "remoteok", AgeDays: 2},
+		{Title: "Senior Backend Engineer (Remote)", Company: "ACME", Location: "Austin, Texas", URL: "https://b/1", Source: "google", AgeDays: 5, Salary: "$150k"},
+		{Title: "Platform Engineer", Company: "Acme", URL: "https://c/1", Source: "web3", Description: desc},
+		{Title: "Staff Platform Eng", Company: "Acme", URL: "https://d/1", Source: "google", Description: desc + "."},
+		{Title: "Senior Backend Engineer", Company: "Other", Location: "Austin, TX", URL: "https://e/1"},
+		{Title: "Senior Backend Engineer", Company: "Acme", Location: "Denver, CO", URL: "https://f/1"},
+		{Title: "Senior Backend Engineer", Company: "Acme", URL: "https://g/1"},
+	}
+
+	merged, dupes := mergeDuplicates(jobs)
+	if dupes != 2 || len(merged) != 5 {
+		t.Fatalf("got %d merged, %d dupes; want 5, 2", len(merged), dupes)
+	}
+
+	first := merged[0]
+	if first.Source != "remoteok,google" || first.Salary != "$150k" || first.AgeDays != 5 {
+		t.Errorf("merged posting = %+v", first)
+	}
+	if len(first.AltURLs) != 1 || first.AltURLs[0] != "https://b/1" {
+		t.Errorf("AltURLs = %v", first.AltURLs)
+	}
+	if len(merged[1].AltURLs) != 1 || merged[1].AltURLs[0] != "https://d/1" {
+		t.Errorf("fingerprint match not merged: %+v", merged[1])
+	}
+}
+
+func TestSameLocation(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"Austin, TX", "Austin, Texas", true},
+		{"Austin, TX", "Denver, CO", false},
+		{"Remote - US", "Remote (United States)", true},
+		{"Remote - US", "Remote - Europe", false},
+		{"", "", false},
+		{"Austin, TX", "", false},
+	}
+	for _, tt := range tests {
+		if got := sameLocation(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameLocation(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
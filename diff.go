@@ -0,0 +1,531 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	diffBase string
+	diffHTML bool
+)
+
+// resumeChange is one semantic difference between a template and the
+// resume tailored from it.
+type resumeChange struct {
+	Kind   string // moved, edited, removed, added, skill_removed, skill_added, header
+	Where  string
+	Old    string
+	New    string
+	OldPos int // 1-based position in the original list, 0 if none
+	NewPos int
+	Detail string
+
+	orig, tailored         *Bullet
+	origList, tailoredList *ResumeList
+	origRole, tailoredRole *ResumeRole
+	row                    *SkillRow
+	item                   string
+}
+
+// diffResumes lists the changes from original to tailored: bullets moved,
+// edited, removed or added within each list, skill items removed or added,
+// and changed role headers.
+func diffResumes(original, tailored string) ([]resumeChange, error) {
+	origDoc, err := parseResume(original)
+	if err != nil {
+		return nil, fmt.Errorf("parsing original: %v", err)
+	}
+	newDoc, err := parseResume(tailored)
+	if err != nil {
+		return nil, fmt.Errorf("parsing tailored resume: %v", err)
+	}
+	return diffDocs(origDoc, newDoc), nil
+}
+
+// diffDocs is diffResumes on parsed documents. The changes point into both
+// documents, so a review can apply them to newDoc.
+func diffDocs(origDoc, newDoc *ResumeDoc) []resumeChange {
+	changes := diffHeaders(origDoc.Roles(), newDoc.Roles())
+	for _, pair := range pairLists(origDoc, newDoc) {
+		changes = append(changes, diffBullets(pair)...)
+	}
+	return append(changes, diffSkills(origDoc.SkillRows(), newDoc.SkillRows())...)
+}
+
+// diffHeaders reports changed employers, titles, dates and locations, the
+// same fields roleDrift checks.
+func diffHeaders(orig, tailored []*ResumeRole) []resumeChange {
+	var changes []resumeChange
+	for i, r := range tailored {
+		var match *ResumeRole
+		for _, o := range orig {
+			if strings.EqualFold(strings.TrimSpace(o.Employer), strings.TrimSpace(r.Employer)) {
+				match = o
+				break
+			}
+		}
+		if match == nil {
+			if i < len(orig) {
+				changes = append(changes, resumeChange{Kind: "header", Where: fmt.Sprintf("role %d", i+1), Detail: "employer",
+					Old: latexPlain(orig[i].Employer), New: latexPlain(r.Employer), origRole: orig[i], tailoredRole: r})
+			}
+			continue
+		}
+		for _, field := range []struct{ name, was, now string }{
+			{"title", match.Title, r.Title},
+			{"dates", match.Dates, r.Dates},
+			{"location", match.Location, r.Location},
+		} {
+			if strings.TrimSpace(field.was) != strings.TrimSpace(field.now) {
+				changes = append(changes, resumeChange{Kind: "header", Where: latexPlain(r.Employer), Detail: field.name,
+					Old: latexPlain(field.was), New: latexPlain(field.now), origRole: match, tailoredRole: r})
+			}
+		}
+	}
+	return changes
+}
+
+type listPair struct {
+	where          string
+	orig, tailored *ResumeList
+}
+
+// pairLists matches each list in tailored with its list in original: roles
+// by employer, other lists by section title and position in the section.
+func pairLists(orig, tailored *ResumeDoc) []listPair {
+	type keyed struct {
+		key, where string
+		list       *ResumeList
+	}
+	collect := func(doc *ResumeDoc) []keyed {
+		var lists []keyed
+		for _, s := range doc.Sections {
+			title := latexPlain(s.Title)
+			n := 0
+			for _, b := range s.Blocks {
+				switch {
+				case b.Role != nil:
+					employer := latexPlain(b.Role.Employer)
+					lists = append(lists, keyed{"role:" + strings.ToLower(employer), employer, &b.Role.ResumeList})
+				case b.List != nil:
+					n++
+					where := title
+					if n > 1 {
+						where = fmt.Sprintf("%s (list %d)", title, n)
+					}
+					lists = append(lists, keyed{fmt.Sprintf("list:%s:%d", strings.ToLower(title), n), where, b.List})
+				}
+			}
+		}
+		return lists
+	}
+
+	origLists := collect(orig)
+	used := make(map[string]bool)
+	var pairs []listPair
+	for _, t := range collect(tailored) {
+		p := listPair{where: t.where, tailored: t.list}
+		for _, o := range origLists {
+			if o.key == t.key && !used[o.key] {
+				p.orig = o.list
+				used[o.key] = true
+				break
+			}
+		}
+		pairs = append(pairs, p)
+	}
+	for _, o := range origLists {
+		if !used[o.key] {
+			pairs = append(pairs, listPair{where: o.where, orig: o.list})
+		}
+	}
+	return pairs
+}
+
+func diffBullets(p listPair) []resumeChange {
+	var origBullets, newBullets []*Bullet
+	if p.orig != nil {
+		origBullets = p.orig.Bullets
+	}
+	if p.tailored != nil {
+		newBullets = p.tailored.Bullets
+	}
+	origPos := make(map[*Bullet]int)
+	for i, b := range origBullets {
+		origPos[b] = i
+	}
+	match := matchBullets(origBullets, newBullets)
+	used := make(map[*Bullet]bool)
+	for _, o := range match {
+		used[o] = true
+	}
+
+	positions := make([]int, len(newBullets))
+	for i, o := range match {
+		positions[i] = -1
+		if o != nil {
+			positions[i] = origPos[o]
+		}
+	}
+	inOrder := longestIncreasing(positions)
+
+	var changes []resumeChange
+	base := resumeChange{Where: p.where, origList: p.orig, tailoredList: p.tailored}
+	for i, b := range newBullets {
+		c := base
+		c.New, c.NewPos, c.tailored = latexPlain(b.Text), i+1, b
+		o := match[i]
+		if o == nil {
+			c.Kind = "added"
+			changes = append(changes, c)
+			continue
+		}
+		c.Old, c.OldPos, c.orig = latexPlain(o.Text), origPos[o]+1, o
+		if !inOrder[i] {
+			c.Kind = "moved"
+			changes = append(changes, c)
+		}
+		if o.Text != b.Text {
+			c.Kind = "edited"
+			changes = append(changes, c)
+		}
+	}
+	for i, o := range origBullets {
+		if !used[o] {
+			c := base
+			c.Kind, c.Old, c.OldPos, c.orig = "removed", latexPlain(o.Text), i+1, o
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// matchBullets pairs each tailored bullet with its original, or nil when it
+// is new. Identical bullets pair first, then each remaining tailored bullet
+// takes its closest original.
+func matchBullets(origBullets, newBullets []*Bullet) []*Bullet {
+	match := make([]*Bullet, len(newBullets))
+	used := make(map[*Bullet]bool)
+	for i, b := range newBullets {
+		for _, o := range origBullets {
+			if !used[o] && o.Text == b.Text {
+				match[i], used[o] = o, true
+				break
+			}
+		}
+	}
+	for i, b := range newBullets {
+		if match[i] == nil {
+			if o := closestBullet(origBullets, b, used); o != nil {
+				match[i], used[o] = o, true
+			}
+		}
+	}
+	return match
+}
+
+func diffSkills(orig, tailored []*SkillRow) []resumeChange {
+	byCategory := make(map[string]*SkillRow)
+	for _, r := range tailored {
+		byCategory[strings.ToLower(latexPlain(r.Category))] = r
+	}
+	has := func(r *SkillRow, item string) bool {
+		if r == nil {
+			return false
+		}
+		for _, i := range r.Items {
+			if strings.EqualFold(latexPlain(i), latexPlain(item)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var changes []resumeChange
+	seen := make(map[string]bool)
+	for _, o := range orig {
+		key := strings.ToLower(latexPlain(o.Category))
+		seen[key] = true
+		t := byCategory[key]
+		for i, item := range o.Items {
+			if !has(t, item) {
+				changes = append(changes, resumeChange{Kind: "skill_removed", Where: latexPlain(o.Category), Old: latexPlain(item), OldPos: i + 1, row: o, item: item})
+			}
+		}
+		if t == nil {
+			continue
+		}
+		for i, item := range t.Items {
+			if !has(o, item) {
+				changes = append(changes, resumeChange{Kind: "skill_added", Where: latexPlain(t.Category), New: latexPlain(item), NewPos: i + 1, row: t, item: item})
+			}
+		}
+	}
+	for _, t := range tailored {
+		if seen[strings.ToLower(latexPlain(t.Category))] {
+			continue
+		}
+		for i, item := range t.Items {
+			changes = append(changes, resumeChange{Kind: "skill_added", Where: latexPlain(t.Category), New: latexPlain(item), NewPos: i + 1, row: t, item: item})
+		}
+	}
+	return changes
+}
+
+// diffSpan is a run of words that is unchanged ('='), removed ('-') or
+// added ('+').
+type diffSpan struct {
+	Op   byte
+	Text string
+}
+
+// wordDiff compares a and b word by word.
+func wordDiff(a, b string) []diffSpan {
+	aw, bw := strings.Fields(a), strings.Fields(b)
+	lcs := make([][]int, len(aw)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(bw)+1)
+	}
+	for i := len(aw) - 1; i >= 0; i-- {
+		for j := len(bw) - 1; j >= 0; j-- {
+			if aw[i] == bw[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var spans []diffSpan
+	add := func(op byte, word string) {
+		if n := len(spans); n > 0 && spans[n-1].Op == op {
+			spans[n-1].Text += " " + word
+			return
+		}
+		spans = append(spans, diffSpan{op, word})
+	}
+	i, j := 0, 0
+	for i < len(aw) || j < len(bw) {
+		switch {
+		case i < len(aw) && j < len(bw) && aw[i] == bw[j]:
+			add('=', aw[i])
+			i++
+			j++
+		case i < len(aw) && (j == len(bw) || lcs[i+1][j] >= lcs[i][j+1]):
+			add('-', aw[i])
+			i++
+		default:
+			add('+', bw[j])
+			j++
+		}
+	}
+	return spans
+}
+
+func coloredWordDiff(a, b string) string {
+	var parts []string
+	for _, s := range wordDiff(a, b) {
+		switch s.Op {
+		case '-':
+			parts = append(parts, color.New(color.FgRed, color.CrossedOut).Sprint(s.Text))
+		case '+':
+			parts = append(parts, color.GreenString(s.Text))
+		default:
+			parts = append(parts, s.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c resumeChange) describe() string {
+	switch c.Kind {
+	case "moved":
+		return fmt.Sprintf("%s moved %d → %d: %s", color.CyanString("↕"), c.OldPos, c.NewPos, c.New)
+	case "edited":
+		return fmt.Sprintf("%s edited %d: %s", color.YellowString("~"), c.NewPos, coloredWordDiff(c.Old, c.New))
+	case "removed":
+		return fmt.Sprintf("%s removed %d: %s", color.RedString("−"), c.OldPos, color.RedString(c.Old))
+	case "added":
+		return fmt.Sprintf("%s added %d: %s", color.GreenString("+"), c.NewPos, color.GreenString(c.New))
+	case "skill_removed":
+		return fmt.Sprintf("%s skill %s", color.RedString("−"), color.RedString(c.Old))
+	case "skill_added":
+		return fmt.Sprintf("%s skill %s", color.GreenString("+"), color.GreenString(c.New))
+	case "header":
+		return fmt.Sprintf("%s %s: %s", color.YellowString("~"), c.Detail, coloredWordDiff(c.Old, c.New))
+	}
+	return c.Kind
+}
+
+func printResumeDiff(w io.Writer, changes []resumeChange) {
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "%s No changes\n", color.GreenString("✓"))
+		return
+	}
+	last := ""
+	for _, c := range changes {
+		if c.Where != last {
+			fmt.Fprintf(w, "\n%s\n", color.New(color.Bold).Sprint(c.Where))
+			last = c.Where
+		}
+		fmt.Fprintf(w, "  %s\n", c.describe())
+	}
+}
+
+var resumeDiffTemplate = template.Must(template.New("diff").Funcs(template.FuncMap{
+	"words": wordDiff,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: -apple-system, Helvetica, Arial, sans-serif; max-width: 50em; margin: 2em auto; padding: 0 1em; color: #222; }
+h2 { font-size: 1.05em; margin: 1.5em 0 0.4em; border-bottom: 1px solid #ddd; }
+li { margin: 0.3em 0; list-style: none; }
+.tag { display: inline-block; min-width: 6.5em; font-size: 0.8em; color: #666; }
+del { background: #fdd; color: #900; }
+ins { background: #dfd; color: #060; text-decoration: none; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Base}} → {{.Tailored}}</p>
+{{if not .Changes}}<p>No changes.</p>{{end}}
+{{range .Groups}}<h2>{{.Where}}</h2>
+<ul>
+{{range .Changes}}<li>{{if eq .Kind "moved"}}<span class="tag">moved {{.OldPos}} → {{.NewPos}}</span>{{.New}}
+{{else if or (eq .Kind "edited") (eq .Kind "header")}}<span class="tag">{{if eq .Kind "header"}}{{.Detail}}{{else}}edited {{.NewPos}}{{end}}</span>{{range words .Old .New}}{{if eq .Op 45}}<del>{{.Text}}</del> {{else if eq .Op 43}}<ins>{{.Text}}</ins> {{else}}{{.Text}} {{end}}{{end}}
+{{else if or (eq .Kind "removed") (eq .Kind "skill_removed")}}<span class="tag">{{if eq .Kind "removed"}}removed {{.OldPos}}{{else}}skill removed{{end}}</span><del>{{.Old}}</del>
+{{else}}<span class="tag">{{if eq .Kind "added"}}added {{.NewPos}}{{else}}skill added{{end}}</span><ins>{{.New}}</ins>
+{{end}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>
+`))
+
+func resumeDiffHTML(title, base, tailored string, changes []resumeChange) (string, error) {
+	type group struct {
+		Where   string
+		Changes []resumeChange
+	}
+	var groups []group
+	for _, c := range changes {
+		if len(groups) == 0 || groups[len(groups)-1].Where != c.Where {
+			groups = append(groups, group{Where: c.Where})
+		}
+		groups[len(groups)-1].Changes = append(groups[len(groups)-1].Changes, c)
+	}
+	var b bytes.Buffer
+	err := resumeDiffTemplate.Execute(&b, map[string]interface{}{
+		"Title":    title,
+		"Base":     base,
+		"Tailored": tailored,
+		"Changes":  changes,
+		"Groups":   groups,
+	})
+	return b.String(), err
+}
+
+// resolveResultsDir takes a results directory or a job query and returns
+// the results directory.
+func resolveResultsDir(arg string) (string, error) {
+	if info, err := os.Stat(arg); err == nil && info.IsDir() {
+		return filepath.Clean(arg), nil
+	}
+	if err := InitDB(); err != nil {
+		return "", err
+	}
+	job, err := FindJobByQuery(arg)
+	if err != nil {
+		return "", err
+	}
+	dir, err := findLatestOutputDir(job.ID)
+	if err != nil {
+		return "", fmt.Errorf("no match run found for [%d] %s (run 'resumectl match' first)", job.ID, job.Company)
+	}
+	return dir, nil
+}
+
+// findSourceTemplate returns the template whose content hash was recorded
+// as the source of the match run in outputDir.
+func findSourceTemplate(outputDir string) (string, error) {
+	if db == nil {
+		if err := InitDB(); err != nil {
+			return "", fmt.Errorf("no database to look up the source template (pass --base): %v", err)
+		}
+	}
+	hash, err := FindSourceHash(outputDir)
+	if err == sql.ErrNoRows || hash == "" {
+		return "", fmt.Errorf("no match run recorded for %s (pass --base)", outputDir)
+	}
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(assembledPath(hash)); err == nil {
+		return assembledPath(hash), nil
+	}
+	if path := findTemplateByHash(hash); path != "" {
+		return path, nil
+	}
+	return "", fmt.Errorf("no template matches source hash %s; it may have been edited or pruned since (pass --base)", hash)
+}
+
+func runDiff(cmd *cobra.Command, args []string) {
+	dir, err := resolveResultsDir(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	base := diffBase
+	if base == "" {
+		if base, err = findSourceTemplate(dir); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	original, err := os.ReadFile(base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", base, err)
+		os.Exit(1)
+	}
+	tailoredPath := filepath.Join(dir, "resume.tex")
+	tailored, err := os.ReadFile(tailoredPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", tailoredPath, err)
+		os.Exit(1)
+	}
+	changes, err := diffResumes(string(original), string(tailored))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s → %s\n", base, tailoredPath)
+	printResumeDiff(os.Stdout, changes)
+
+	if diffHTML {
+		page, err := resumeDiffHTML("Resume changes: "+dir, base, tailoredPath, changes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering HTML: %v\n", err)
+			os.Exit(1)
+		}
+		out := filepath.Join(dir, "diff.html")
+		if err := os.WriteFile(out, []byte(page), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", out, err)
+			os.Exit(1)
+		}
+		fmt.Printf("\n%s Wrote %s\n", color.GreenString("✓"), out)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestWordDiff(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"Mentored 4 engineers", "Mentored 4 engineers", "=Mentored 4 engineers"},
+		{"Mentored 4 engineers", "Mentored 4 data engineers", "=Mentored 4|+data|=engineers"},
+		{"Cut nightly batch runtime by 60%", "Cut batch runtime by 60% using Spark", "=Cut|-nightly|=batch runtime by 60%|+using Spark"},
+		{"Led migration", "Drove migration", "-Led|+Drove|=migration"},
+		{"", "new", "+new"},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, s := range wordDiff(tt.a, tt.b) {
+			got = append(got, string(s.Op)+s.Text)
+		}
+		if strings.Join(got, "|") != tt.want {
+			t.Errorf("wordDiff(%q, %q) = %q, want %q", tt.a, tt.b, strings.Join(got, "|"), tt.want)
+		}
+	}
+}
+
+func TestDiffResumes(t *testing.T) {
+	tailored := strings.NewReplacer(
+		"  \\item Built a streaming platform on Kafka and Flink processing 2B events/day\n  \\item Led migration from Airflow 1 to Airflow 2\n    across 300 DAGs % multi-line bullet\n  % resumectl:pin-top\n  \\item Mentored 4 engineers\n",
+		"  % resumectl:pin-top\n  \\item Mentored 4 data engineers\n  \\item Built a streaming platform on Kafka and Flink processing 2B events/day\n",
+		"\\item Cut nightly batch runtime by 60\\%\n", "\\item Cut nightly batch runtime by 60\\%\n\\item Wrote the on-call runbook\n",
+		"Kafka, Flink, Spark, {dbt, Airflow}", "Kafka, Flink, {dbt, Airflow}, Terraform",
+		"{Data Engineer}{Austin, TX}", "{Senior Data Engineer}{Austin, TX}",
+	).Replace(sampleResume)
+
+	changes, err := diffResumes(sampleResume, tailored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, c := range changes {
+		got = append(got, fmt.Sprintf("%s %s %d→%d %s|%s", c.Kind, c.Where, c.OldPos, c.NewPos, c.Old, c.New))
+	}
+	want := []string{
+		"header Initech 0→0 Data Engineer|Senior Data Engineer",
+		"moved Acme Corp 3→1 Mentored 4 engineers|Mentored 4 data engineers",
+		"edited Acme Corp 3→1 Mentored 4 engineers|Mentored 4 data engineers",
+		"removed Acme Corp 2→0 Led migration from Airflow 1 to Airflow 2 across 300 DAGs|",
+		"added Initech 0→3 |Wrote the on-call runbook",
+		"skill_removed Data 3→0 Spark|",
+		"skill_added Data 0→4 |Terraform",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	if changes, _ := diffResumes(sampleResume, sampleResume); len(changes) != 0 {
+		t.Errorf("identical resumes: %d changes", len(changes))
+	}
+}
+
+func TestResumeDiffHTML(t *testing.T) {
+	changes := []resumeChange{
+		{Kind: "edited", Where: "Acme <Corp>", Old: "Led migration", New: "Drove migration", NewPos: 2},
+		{Kind: "skill_removed", Where: "Data", Old: "Spark"},
+	}
+	page, err := resumeDiffHTML("Resume changes", "base.tex", "resume.tex", changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<h2>Acme &lt;Corp&gt;</h2>", "<del>Led</del> <ins>Drove</ins> migration", "<del>Spark</del>"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("missing %q in:\n%s", want, page)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// driftSimilarityThreshold is the minimum word overlap (Dice coefficient)
+// a tailored bullet needs with some original bullet to count as the same
+// claim reordered or lightly edited.
+const driftSimilarityThreshold = 0.5
+
+type DriftFinding struct {
+	Kind     string `json:"kind"`
+	Where    string `json:"where"`
+	Detail   string `json:"detail"`
+	Text     string `json:"text"`
+	Original string `json:"original,omitempty"`
+}
+
+func (f DriftFinding) String() string {
+	s := fmt.Sprintf("[%s] %s: %s", f.Where, strings.ReplaceAll(f.Kind, "_", " "), f.Detail)
+	if f.Text != "" && f.Text != f.Detail {
+		s += fmt.Sprintf(" — %q", f.Text)
+	}
+	return s
+}
+
+var (
+	latexCommandRe = regexp.MustCompile(`\\[A-Za-z]+\*?`)
+	driftTokenRe   = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9+#./-]*[A-Za-z0-9+#]|[A-Za-z0-9]`)
+	driftNumberRe  = regexp.MustCompile(`\d+(?:[.,]\d+)*`)
+)
+
+var driftStopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "or": true, "of": true, "to": true, "in": true,
+	"on": true, "for": true, "with": true, "by": true, "from": true, "at": true, "as": true, "into": true,
+	"across": true, "over": true, "via": true, "using": true, "that": true, "which": true, "while": true,
+	"its": true, "their": true, "our": true, "is": true, "was": true, "were": true, "be": true,
+}
+
+// latexPlain reduces a LaTeX fragment to the words a reader would see.
+func latexPlain(s string) string {
+	s = strings.NewReplacer(`\%`, "%", `\&`, "&", `\$`, "$", `\_`, "_", `\#`, "#", "---", "-", "--", "-", "~", " ").Replace(s)
+	s = latexCommandRe.ReplaceAllString(s, " ")
+	s = strings.NewReplacer("{", "", "}", "", "\\", " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func driftTokens(text string) []string {
+	return driftTokenRe.FindAllString(text, -1)
+}
+
+func contentWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, t := range driftTokens(text) {
+		t = strings.ToLower(t)
+		if !driftStopwords[t] {
+			words[t] = true
+		}
+	}
+	return words
+}
+
+func wordSimilarity(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	shared := 0
+	for w := range a {
+		if b[w] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+func numbersIn(text string) map[string]bool {
+	nums := make(map[string]bool)
+	for _, n := range driftNumberRe.FindAllString(text, -1) {
+		nums[strings.ReplaceAll(n, ",", "")] = true
+	}
+	return nums
+}
+
+// ordinaryFirstWords start bullets and requirement lines, capitalized only
+// because they come first.
+var ordinaryFirstWords = map[string]bool{
+	"lead": true, "led": true, "own": true, "owned": true, "build": true, "built": true, "run": true, "ran": true,
+	"drive": true, "drove": true, "grow": true, "grew": true, "cut": true, "set": true, "wrote": true, "write": true,
+	"design": true, "develop": true, "manage": true, "mentor": true, "deliver": true, "ship": true, "shipped": true,
+	"strong": true, "deep": true, "solid": true, "proven": true, "excellent": true, "experience": true,
+	"expertise": true, "knowledge": true, "familiarity": true, "background": true, "ability": true,
+	"understanding": true, "hands": true, "senior": true, "new": true, "multiple": true, "several": true,
+	"this": true, "these": true, "all": true, "each": true, "most": true, "over": true, "more": true, "both": true,
+}
+
+// isOrdinaryWord reports whether a lower-case word is a stop word, a common
+// opener or a past-tense verb, the usual way a resume bullet begins.
+func isOrdinaryWord(lower string) bool {
+	return driftStopwords[lower] || ordinaryFirstWords[lower] || len(lower) > 4 && strings.HasSuffix(lower, "ed")
+}
+
+// looksLikeTerm reports whether a token reads as a proper noun or technology
+// name rather than an ordinary word. A capitalized first word counts unless
+// it is an ordinary word.
+func looksLikeTerm(tok string, first bool) bool {
+	hasLetter, hasUpper, hasSymbol := false, false, false
+	for i, r := range tok {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			hasLetter = true
+			if i > 0 {
+				hasUpper = true
+			}
+		case r >= 'a' && r <= 'z':
+			hasLetter = true
+		case r >= '0' && r <= '9', r == '+', r == '#', r == '.', r == '/':
+			hasSymbol = true
+		}
+	}
+	if !hasLetter {
+		return false
+	}
+	if hasUpper || hasSymbol {
+		return true
+	}
+	if tok[0] < 'A' || tok[0] > 'Z' {
+		return false
+	}
+	return !first || !isOrdinaryWord(strings.ToLower(tok))
+}
+
+// detectDrift aligns every tailored bullet with its closest original bullet
+// and reports new numbers, new proper nouns or technologies, bullets that
+// match nothing in the original, skills that weren't there before, and
+// changed employers, titles, dates or locations.
+func detectDrift(original, tailored string) []DriftFinding {
+	origDoc, err := parseResume(original)
+	if err != nil {
+		return []DriftFinding{{Kind: "unparsed", Where: "resume", Detail: "could not parse original: " + err.Error()}}
+	}
+	newDoc, err := parseResume(tailored)
+	if err != nil {
+		return []DriftFinding{{Kind: "unparsed", Where: "resume", Detail: "could not parse tailored resume: " + err.Error()}}
+	}
+
+	var findings []DriftFinding
+	findings = append(findings, roleDrift(origDoc.Roles(), newDoc.Roles())...)
+
+	origPlain := latexPlain(original)
+	origVocab := make(map[string]bool)
+	for _, t := range driftTokens(origPlain) {
+		origVocab[strings.ToLower(t)] = true
+	}
+	origNumbers := numbersIn(origPlain)
+
+	type origBullet struct {
+		text    string
+		words   map[string]bool
+		numbers map[string]bool
+	}
+	var origBullets []origBullet
+	for _, b := range origDoc.Bullets() {
+		text := latexPlain(b.Text)
+		origBullets = append(origBullets, origBullet{text, contentWords(text), numbersIn(text)})
+	}
+
+	for _, b := range newDoc.Bullets() {
+		text := latexPlain(b.Text)
+		words := contentWords(text)
+
+		best, bestScore := -1, 0.0
+		for i, ob := range origBullets {
+			if s := wordSimilarity(words, ob.words); s > bestScore {
+				best, bestScore = i, s
+			}
+		}
+		closest := ""
+		if best >= 0 {
+			closest = origBullets[best].text
+		}
+
+		if bestScore == 1 && best >= 0 && text == closest {
+			continue
+		}
+
+		for n := range numbersIn(text) {
+			switch {
+			case !origNumbers[n]:
+				findings = append(findings, DriftFinding{Kind: "new_number", Where: b.ID, Detail: n + " does not appear in the original", Text: text, Original: closest})
+			case best < 0 || !origBullets[best].numbers[n]:
+				findings = append(findings, DriftFinding{Kind: "moved_number", Where: b.ID, Detail: n + " comes from a different bullet", Text: text, Original: closest})
+			}
+		}
+
+		seen := make(map[string]bool)
+		for i, tok := range driftTokens(text) {
+			lower := strings.ToLower(tok)
+			if origVocab[lower] || seen[lower] || !looksLikeTerm(tok, i == 0) || driftNumberRe.MatchString(tok) && !strings.ContainsAny(tok, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+				continue
+			}
+			seen[lower] = true
+			findings = append(findings, DriftFinding{Kind: "new_term", Where: b.ID, Detail: tok, Text: text, Original: closest})
+		}
+
+		if bestScore < driftSimilarityThreshold {
+			findings = append(findings, DriftFinding{
+				Kind:     "low_similarity",
+				Where:    b.ID,
+				Detail:   fmt.Sprintf("closest original bullet is %.0f%% similar", bestScore*100),
+				Text:     text,
+				Original: closest,
+			})
+		}
+	}
+
+	origSkills := make(map[string]bool)
+	for _, r := range origDoc.SkillRows() {
+		for _, item := range r.Items {
+			origSkills[strings.ToLower(latexPlain(item))] = true
+		}
+	}
+	for _, r := range newDoc.SkillRows() {
+		for _, item := range r.Items {
+			plain := latexPlain(item)
+			if !origSkills[strings.ToLower(plain)] && !origVocab[strings.ToLower(plain)] {
+				findings = append(findings, DriftFinding{Kind: "new_skill", Where: r.Category, Detail: plain})
+			}
+		}
+	}
+
+	return findings
+}
+
+func roleDrift(orig, tailored []*ResumeRole) []DriftFinding {
+	var findings []DriftFinding
+	for i, r := range tailored {
+		var match *ResumeRole
+		for _, o := range orig {
+			if strings.EqualFold(strings.TrimSpace(o.Employer), strings.TrimSpace(r.Employer)) {
+				match = o
+				break
+			}
+		}
+		where := fmt.Sprintf("role %d", i+1)
+		if match == nil {
+			f := DriftFinding{Kind: "changed_employer", Where: where, Detail: latexPlain(r.Employer), Text: latexPlain(r.Employer)}
+			if i < len(orig) {
+				f.Original = latexPlain(orig[i].Employer)
+				f.Detail = fmt.Sprintf("%s → %s", f.Original, f.Text)
+			}
+			findings = append(findings, f)
+			continue
+		}
+		for _, field := range []struct{ kind, was, now string }{
+			{"changed_title", match.Title, r.Title},
+			{"changed_dates", match.Dates, r.Dates},
+			{"changed_location", match.Location, r.Location},
+		} {
+			if strings.TrimSpace(field.was) != strings.TrimSpace(field.now) {
+				was, now := latexPlain(field.was), latexPlain(field.now)
+				findings = append(findings, DriftFinding{Kind: field.kind, Where: latexPlain(r.Employer), Detail: fmt.Sprintf("%s → %s", was, now), Text: now, Original: was})
+			}
+		}
+	}
+	return findings
+}
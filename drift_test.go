@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectDrift(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []string
+	}{
+		{"unchanged", "", "", nil},
+		{"reordered bullets",
+			"  \\item Built a streaming platform on Kafka and Flink processing 2B events/day\n  \\item Led migration",
+			"  \\item Led migration",
+			nil},
+		{"light rewording",
+			"Designed the warehouse schema in Snowflake",
+			"Designed the analytics warehouse schema in Snowflake",
+			nil},
+		{"invented metric",
+			"Designed the warehouse schema in Snowflake",
+			"Designed the warehouse schema in Snowflake, reducing query latency 40\\%",
+			[]string{"new_number"}},
+		{"metric borrowed from another bullet",
+			"Designed the warehouse schema in Snowflake",
+			"Designed the warehouse schema in Snowflake for 300 analysts",
+			[]string{"moved_number"}},
+		{"new technology",
+			"Designed the warehouse schema in Snowflake",
+			"Designed the warehouse schema in Snowflake and BigQuery",
+			[]string{"new_term"}},
+		{"sentence-initial verb is not a term",
+			"Designed the warehouse schema in Snowflake",
+			"Architected the warehouse schema in Snowflake",
+			nil},
+		{"new technology as the first word",
+			"Designed the warehouse schema in Snowflake",
+			"Databricks warehouse schema designed in Snowflake",
+			[]string{"new_term"}},
+		{"unfamiliar opening verb is not a term",
+			"Designed the warehouse schema in Snowflake",
+			"Spearheaded the warehouse schema in Snowflake",
+			nil},
+		{"new claim",
+			"Mentored 4 engineers",
+			"Owned the company hiring pipeline",
+			[]string{"low_similarity"}},
+		{"changed title",
+			"{Staff Data Engineer}",
+			"{Principal Data Engineer}",
+			[]string{"changed_title"}},
+		{"changed dates",
+			"{2017 -- 2020}",
+			"{2016 -- 2020}",
+			[]string{"changed_dates"}},
+		{"changed employer",
+			"{Initech}",
+			"{Initrode}",
+			[]string{"changed_employer"}},
+		{"new skill",
+			"Go, Python, SQL, Scala",
+			"Go, Python, SQL, Scala, Rust",
+			[]string{"new_skill"}},
+		{"skill substring is not a match",
+			"Go, Python, SQL, Scala",
+			"Go, Python, SQL, Scala, R",
+			[]string{"new_skill"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tailored := sampleResume
+			if tt.old != "" {
+				if !strings.Contains(sampleResume, tt.old) {
+					t.Fatalf("fixture does not contain %q", tt.old)
+				}
+				tailored = strings.Replace(sampleResume, tt.old, tt.new, 1)
+			}
+			var got []string
+			for _, f := range detectDrift(sampleResume, tailored) {
+				got = append(got, f.Kind)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", detectDrift(sampleResume, tailored), tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectDriftUnparsable(t *testing.T) {
+	got := detectDrift(sampleResume, strings.Replace(sampleResume, "\\end{rSubsection}", "", 1))
+	if len(got) != 1 || got[0].Kind != "unparsed" {
+		t.Errorf("got %v", got)
+	}
+}
+
+func TestLatexPlain(t *testing.T) {
+	tests := map[string]string{
+		`Cut runtime by 60\%`:                 "Cut runtime by 60%",
+		`\textbf{Go} \& {Python}`:             "Go & Python",
+		`Jan 2021 -- Present`:                 "Jan 2021 - Present",
+		`Built~it \emph{fast}`:                "Built it fast",
+		`Kafka, Flink, Spark, {dbt, Airflow}`: "Kafka, Flink, Spark, dbt, Airflow",
+	}
+	for in, want := range tests {
+		if got := latexPlain(in); got != want {
+			t.Errorf("latexPlain(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type CompanyRule struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+type ExclusionRules struct {
+	Companies            []CompanyRule `json:"companies"`
+	TitlePatterns        []string      `json:"title_patterns"`
+	DescriptionKeywords  []string      `json:"description_keywords"`
+	RejectedWithinMonths int           `json:"rejected_within_months"`
+
+	titleRes          []*regexp.Regexp
+	companyKeys       map[string]string
+	rejectedCompanies map[string]string
+}
+
+func findExclusionRules() string {
+	if _, err := os.Stat("exclude.json"); err == nil {
+		return "exclude.json"
+	}
+	home, _ := os.UserHomeDir()
+	path := filepath.Join(home, ".resumectl", "exclude.json")
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	return ""
+}
+
+func loadExclusionRules(path string) (*ExclusionRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules ExclusionRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("invalid %s: %v", path, err)
+	}
+
+	for _, p := range rules.TitlePatterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid title pattern %q: %v", p, err)
+		}
+		rules.titleRes = append(rules.titleRes, re)
+	}
+
+	rules.companyKeys = make(map[string]string)
+	for _, c := range rules.Companies {
+		for _, name := range append([]string{c.Name}, c.Aliases...) {
+			if key := normalizeCompanyName(name); key != "" {
+				rules.companyKeys[key] = c.Name
+			}
+		}
+	}
+
+	return &rules, nil
+}
+
+func (r *ExclusionRules) loadRejected() error {
+	if r.RejectedWithinMonths <= 0 {
+		return nil
+	}
+	companies, err := RecentlyRejectedCompanies(r.RejectedWithinMonths)
+	if err != nil {
+		return err
+	}
+	r.rejectedCompanies = make(map[string]string)
+	for _, c := range companies {
+		r.rejectedCompanies[normalizeCompanyName(c)] = c
+	}
+	return nil
+}
+
+func (r *ExclusionRules) reason(j ScanResult) string {
+	company := normalizeCompanyName(j.Company)
+	if name, ok := r.companyKeys[company]; ok {
+		return "company blocklist: " + name
+	}
+	if name, ok := r.rejectedCompanies[company]; ok {
+		return fmt.Sprintf("rejected by %s in the last %d months", name, r.RejectedWithinMonths)
+	}
+	for i, re := range r.titleRes {
+		if re.MatchString(j.Title) {
+			return "title matches " + r.TitlePatterns[i]
+		}
+	}
+	desc := strings.ToLower(j.Description)
+	for _, kw := range r.DescriptionKeywords {
+		if kw != "" && strings.Contains(desc, strings.ToLower(kw)) {
+			return "description mentions " + kw
+		}
+	}
+	return ""
+}
+
+func applyExclusions(jobs []ScanResult, rules *ExclusionRules) ([]ScanResult, map[string]int) {
+	var kept []ScanResult
+	reasons := make(map[string]int)
+	for _, j := range jobs {
+		if reason := rules.reason(j); reason != "" {
+			reasons[reason]++
+			continue
+		}
+		kept = append(kept, j)
+	}
+	return kept, reasons
+}
+
+// printDescriptionGaps warns that description_keywords were not checked
+// against jobs whose board gives no description (web3.career lists none).
+func printDescriptionGaps(w io.Writer, jobs []ScanResult, rules *ExclusionRules) {
+	if len(rules.DescriptionKeywords) == 0 {
+		return
+	}
+	counts := make(map[string]int)
+	var sources []string
+	for _, j := range jobs {
+		if strings.TrimSpace(j.Description) != "" {
+			continue
+		}
+		if counts[j.Source] == 0 {
+			sources = append(sources, j.Source)
+		}
+		counts[j.Source]++
+	}
+	sort.Strings(sources)
+	for _, src := range sources {
+		fmt.Fprintf(w, "  Warning: description_keywords not checked for %d %s jobs without a description\n", counts[src], src)
+	}
+}
+
+func printExclusions(w io.Writer, reasons map[string]int) {
+	total := 0
+	var keys []string
+	for k, n := range reasons {
+		total += n
+		keys = append(keys, k)
+	}
+	if total == 0 {
+		return
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if reasons[keys[i]] != reasons[keys[j]] {
+			return reasons[keys[i]] > reasons[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+
+	fmt.Fprintf(w, "Excluded %d jobs:\n", total)
+	for _, k := range keys {
+		fmt.Fprintf(w, "  %3d  %s\n", reasons[k], k)
+	}
+}
+
+var (
+	companySuffixes  = regexp.MustCompile(`\b(inc|incorporated|llc|ltd|limited|corp|corporation|co|company|gmbh|plc|sa|ag|bv|pty)\b`)
+	companyNonWordRe = regexp.MustCompile(`[^a-z0-9 ]+`)
+)
+
+func normalizeCompanyName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.ReplaceAll(name, "&", " and ")
+	name = companyNonWordRe.ReplaceAllString(name, " ")
+	name = companySuffixes.ReplaceAllString(name, " ")
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCompanyName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Acme, Inc.", "acme"},
+		{"ACME Corp", "acme"},
+		{"Smith & Wesson LLC", "smith and wesson"},
+		{"  Data-Dog  ", "data dog"},
+	}
+	for _, tt := range tests {
+		if got := normalizeCompanyName(tt.name); got != tt.want {
+			t.Errorf("normalizeCompanyName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestApplyExclusions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exclude.json")
+	os.WriteFile(path, []byte(`{
+		"companies": [{"name": "Acme Staffing", "aliases": ["Acme Talent"]}],
+		"title_patterns": ["(?i)\\bintern\\b"],
+		"description_keywords": ["Clearance Required"]
+	}`), 0644)
+
+	rules, err := loadExclusionRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := []ScanResult{
+		{Company: "Acme Talent, Inc.", Title: "Data Engineer"},
+		{Company: "Stripe", Title: "Data Engineering Intern"},
+		{Company: "Anduril", Title: "Data Engineer", Description: "Active secret clearance required."},
+		{Company: "Stripe", Title: "Staff Data Engineer", Description: "Remote friendly"},
+	}
+
+	kept, reasons := applyExclusions(jobs, rules)
+	if len(kept) != 1 || kept[0].Title != "Staff Data Engineer" {
+		t.Errorf("kept = %+v, want only the staff role", kept)
+	}
+	want := map[string]int{
+		"company blocklist: Acme Staffing":        1,
+		`title matches (?i)\bintern\b`:            1,
+		"description mentions Clearance Required": 1,
+	}
+	for reason, n := range want {
+		if reasons[reason] != n {
+			t.Errorf("reasons[%q] = %d, want %d (all: %v)", reason, reasons[reason], n, reasons)
+		}
+	}
+}
+
+func TestPrintDescriptionGaps(t *testing.T) {
+	jobs := []ScanResult{
+		{Title: "a", Source: "web3"},
+		{Title: "b", Source: "web3"},
+		{Title: "c", Source: "remoteok", Description: "Go and Kafka"},
+		{Title: "d", Source: "google"},
+	}
+	var b strings.Builder
+	printDescriptionGaps(&b, jobs, &ExclusionRules{DescriptionKeywords: []string{"clearance"}})
+	want := "  Warning: description_keywords not checked for 1 google jobs without a description\n" +
+		"  Warning: description_keywords not checked for 2 web3 jobs without a description\n"
+	if b.String() != want {
+		t.Errorf("got:\n%s", b.String())
+	}
+
+	b.Reset()
+	printDescriptionGaps(&b, jobs, &ExclusionRules{})
+	if b.String() != "" {
+		t.Errorf("warned without description rules: %s", b.String())
+	}
+}
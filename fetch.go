@@ -643,6 +643,7 @@ func fetchGenericJob(jobURL string) (*JobInfo, error) {
 	title = strings.TrimSpace(title)
 
 	var content string
+	var pay SalaryRange
 	doc.Find(`script[type="application/ld+json"]`).Each(func(i int, s *goquery.Selection) {
 		if content != "" {
 			return
@@ -658,6 +659,9 @@ func fetchGenericJob(jobURL string) (*JobInfo, error) {
 			if t, ok := ld["title"].(string); ok && t != "" {
 				title = t
 			}
+			if p, ok := salaryFromJSONLD(ld["baseSalary"]); ok {
+				pay = p
+			}
 		}
 	})
 
@@ -698,5 +702,6 @@ func fetchGenericJob(jobURL string) (*JobInfo, error) {
 		Title:       title,
 		ReqID:       extractReqIDFromURL(jobURL),
 		Description: content,
+		Pay:         pay,
 	}, nil
 }
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	gopdf "github.com/ledongthuc/pdf"
+)
+
+var maxPages int
+
+func pdfPageCount(path string) (int, error) {
+	f, r, err := gopdf.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading PDF: %v", err)
+	}
+	defer f.Close()
+	return r.NumPage(), nil
+}
+
+// pageCounter counts the pages latex compiles to in dir. compiled is the
+// source dir/resume.pdf was built from; that PDF is reused until the source
+// changes.
+type pageCounter struct {
+	dir      string
+	compiled string
+}
+
+func (c *pageCounter) pages(latex string) (int, error) {
+	if latex != c.compiled {
+		if err := os.WriteFile(filepath.Join(c.dir, "resume.tex"), []byte(latex), 0644); err != nil {
+			return 0, err
+		}
+		if _, err := compileResume(c.dir, "resume.tex"); err != nil {
+			return 0, fmt.Errorf("compiling: %v", err)
+		}
+		c.compiled = latex
+	}
+	return pdfPageCount(filepath.Join(c.dir, "resume.pdf"))
+}
+
+// fitToPages removes the lowest-priority bullets and skill items one at a
+// time until measure reports at most limit pages. It returns the trimmed
+// LaTeX and a line per removal.
+func fitToPages(latex string, limit int, measure func(string) (int, error)) (string, []string, error) {
+	var removed []string
+	for {
+		pages, err := measure(latex)
+		if err != nil {
+			return latex, removed, err
+		}
+		if pages <= limit {
+			return latex, removed, nil
+		}
+
+		doc, err := parseResume(latex)
+		if err != nil {
+			return latex, removed, fmt.Errorf("parsing resume: %v", err)
+		}
+		what, ok := trimLowestPriority(doc, lockedLines(latex))
+		if !ok {
+			return latex, removed, fmt.Errorf("still %d pages with nothing left to remove", pages)
+		}
+		removed = append(removed, what)
+		latex = doc.Render()
+	}
+}
+
+// lockedLines returns the trimmed lines inside lock annotations.
+func lockedLines(latex string) map[string]bool {
+	lines := strings.Split(latex, "\n")
+	locked := make(map[string]bool)
+	for _, r := range findLockedRegions(lines) {
+		for _, l := range lines[r.start:r.end] {
+			locked[strings.TrimSpace(l)] = true
+		}
+	}
+	return locked
+}
+
+// trimLowestPriority removes one item from doc. Tailoring puts the most
+// relevant bullets first, so the last bullet of the longest list goes first
+// (later lists, usually older roles, win ties). Lists keep at least one
+// bullet, and pinned or locked bullets stay. Once no bullet can go, the last
+// item of the longest skill row is removed instead.
+func trimLowestPriority(doc *ResumeDoc, locked map[string]bool) (string, bool) {
+	var bestList *ResumeList
+	bestIdx := -1
+	for _, l := range doc.Lists() {
+		if len(l.Bullets) < 2 || (bestList != nil && len(l.Bullets) < len(bestList.Bullets)) {
+			continue
+		}
+		for i := len(l.Bullets) - 1; i > 0; i-- {
+			if removable(l.Bullets[i], locked) {
+				bestList, bestIdx = l, i
+				break
+			}
+		}
+	}
+	if bestList != nil {
+		b := bestList.Bullets[bestIdx]
+		bestList.Bullets = append(bestList.Bullets[:bestIdx], bestList.Bullets[bestIdx+1:]...)
+		return fmt.Sprintf("bullet %s: %s", b.ID, latexPlain(b.Text)), true
+	}
+
+	var bestRow *SkillRow
+	for _, r := range doc.SkillRows() {
+		if len(r.Items) < 2 || locked[strings.TrimSpace(r.raw)] {
+			continue
+		}
+		if bestRow == nil || len(r.Items) >= len(bestRow.Items) {
+			bestRow = r
+		}
+	}
+	if bestRow != nil {
+		item := bestRow.Items[len(bestRow.Items)-1]
+		bestRow.Items = bestRow.Items[:len(bestRow.Items)-1]
+		return fmt.Sprintf("skill %s: %s", latexPlain(bestRow.Category), latexPlain(item)), true
+	}
+	return "", false
+}
+
+func removable(b *Bullet, locked map[string]bool) bool {
+	if isPinned(b) {
+		return false
+	}
+	for _, l := range b.raw {
+		if locked[strings.TrimSpace(l)] {
+			return false
+		}
+	}
+	return true
+}
+
+// fitMatchResult compiles result in outputDir, repairing LaTeX errors, then
+// trims it to limit pages. A failed compile leaves the result as it was. It
+// reports whether outputDir/resume.pdf is built from the final source.
+func fitMatchResult(outputDir, original string, result *MatchResult, limit int, w io.Writer) bool {
+	if limit <= 0 {
+		return false
+	}
+	path := filepath.Join(outputDir, "resume.tex")
+	err := os.MkdirAll(outputDir, 0755)
+	if err == nil {
+		err = os.WriteFile(path, []byte(result.TailoredLatex), 0644)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "%s Page fit skipped: %v\n", color.YellowString("⚠"), err)
+		return false
+	}
+	counter := &pageCounter{dir: outputDir, compiled: result.TailoredLatex}
+	fixes, err := compileWithRepair(outputDir, "resume.tex")
+	if len(fixes) > 0 {
+		compiled, readErr := adoptLatexRepairs(outputDir, original, result, fixes)
+		if err == nil {
+			err = readErr
+		}
+		counter.compiled = compiled
+		fmt.Fprintf(w, "%s Fixed %d LaTeX errors\n", color.YellowString("⚠"), len(fixes))
+	}
+	if err != nil {
+		fmt.Fprintf(w, "%s Page fit skipped: %v\n", color.YellowString("⚠"), err)
+		return false
+	}
+
+	latex, removed, err := fitToPages(result.TailoredLatex, limit, counter.pages)
+	result.TailoredLatex, result.Trimmed = latex, removed
+	if err != nil {
+		fmt.Fprintf(w, "%s Page fit: %v\n", color.YellowString("⚠"), err)
+	} else if len(removed) > 0 {
+		fmt.Fprintf(w, "%s Removed %d items to fit %d page(s)\n", color.YellowString("✂"), len(removed), limit)
+	}
+	return counter.compiled == result.TailoredLatex
+}
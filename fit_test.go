@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// bulletPages pretends every perPage bullets fill a page.
+func bulletPages(perPage int) func(string) (int, error) {
+	return func(latex string) (int, error) {
+		doc, err := parseResume(latex)
+		if err != nil {
+			return 0, err
+		}
+		return (len(doc.Bullets()) + perPage - 1) / perPage, nil
+	}
+}
+
+func TestFitToPages(t *testing.T) {
+	tests := []struct {
+		name    string
+		latex   string
+		perPage int
+		removed []string
+		err     bool
+	}{
+		{"already fits", sampleResume, 6, nil, false},
+		{"drops from the longest list first", sampleResume, 5, []string{
+			"bullet R1.2: Led migration from Airflow 1 to Airflow 2 across 300 DAGs",
+		}, false},
+		{"later list wins a tie", sampleResume, 4, []string{
+			"bullet R1.2: Led migration from Airflow 1 to Airflow 2 across 300 DAGs",
+			"bullet R2.2: Cut nightly batch runtime by 60%",
+		}, false},
+		{"pinned bullet stays", strings.Replace(sampleResume, "  \\item Built a streaming", "  % resumectl:pin-top\n  \\item Built a streaming", 1), 4, []string{
+			"bullet R1.2: Led migration from Airflow 1 to Airflow 2 across 300 DAGs",
+			"bullet R2.2: Cut nightly batch runtime by 60%",
+		}, false},
+		{"locked bullet stays", strings.Replace(sampleResume, "Airflow 2\n", "Airflow 2 % resumectl:lock\n", 1), 5, []string{
+			"bullet R2.2: Cut nightly batch runtime by 60%",
+		}, false},
+		{"gives up when every list is down to one bullet", sampleResume, 1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, removed, err := fitToPages(tt.latex, 1, bulletPages(tt.perPage))
+			if (err != nil) != tt.err {
+				t.Fatalf("err = %v", err)
+			}
+			if tt.err {
+				return
+			}
+			if fmt.Sprint(removed) != fmt.Sprint(tt.removed) {
+				t.Errorf("removed %q, want %q", removed, tt.removed)
+			}
+		})
+	}
+}
+
+func TestTrimLowestPrioritySkills(t *testing.T) {
+	doc, err := parseResume(sampleResume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range doc.Lists() {
+		l.Bullets = l.Bullets[:1]
+	}
+	var got []string
+	for i := 0; i < 3; i++ {
+		what, ok := trimLowestPriority(doc, nil)
+		if !ok {
+			t.Fatal("nothing removed")
+		}
+		got = append(got, what)
+	}
+	want := []string{"skill Data: dbt, Airflow", "skill Languages: Scala", "skill Data: Spark"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if strings.Contains(doc.Render(), "Scala") {
+		t.Error("removed item still rendered")
+	}
+}
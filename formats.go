@@ -0,0 +1,400 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+var outputFormats []string
+
+// resumeFormats renders a resume to the formats written next to resume.tex.
+var resumeFormats = map[string]func(*JSONResume) ([]byte, error){
+	"txt":  renderText,
+	"md":   renderMarkdown,
+	"html": renderHTML,
+	"docx": renderDocx,
+}
+
+var formatContentTypes = map[string]string{
+	"pdf":  "application/pdf",
+	"txt":  "text/plain; charset=utf-8",
+	"md":   "text/markdown; charset=utf-8",
+	"html": "text/html; charset=utf-8",
+	"docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
+func checkFormats(formats []string) error {
+	for _, f := range formats {
+		if _, ok := resumeFormats[f]; !ok {
+			return fmt.Errorf("unknown format %q (available: txt, md, html, docx)", f)
+		}
+	}
+	return nil
+}
+
+// writeResumeFormats renders latex to outputDir/resume.<format> for each
+// format and returns the file names written.
+func writeResumeFormats(outputDir, latex string, formats []string) ([]string, error) {
+	if len(formats) == 0 {
+		return nil, nil
+	}
+	if err := checkFormats(formats); err != nil {
+		return nil, err
+	}
+	doc, err := parseResume(latex)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resume: %v", err)
+	}
+	r := jsonResumeFromDoc(doc)
+
+	var written []string
+	for _, f := range formats {
+		data, err := resumeFormats[f](r)
+		if err != nil {
+			return written, fmt.Errorf("rendering %s: %v", f, err)
+		}
+		name := "resume." + f
+		if err := os.WriteFile(filepath.Join(outputDir, name), data, 0644); err != nil {
+			return written, err
+		}
+		written = append(written, name)
+	}
+	return written, nil
+}
+
+// outlineSection is a resume section flattened for the non-LaTeX renderers.
+type outlineSection struct {
+	Title      string
+	Paragraphs []string
+	Entries    []outlineEntry
+	Bullets    []string
+}
+
+type outlineEntry struct {
+	Title    string
+	Subtitle string
+	Dates    string
+	Bullets  []string
+}
+
+func (r *JSONResume) contactLine() []string {
+	var contact []string
+	if loc := r.Basics.Location; loc != nil {
+		var parts []string
+		for _, p := range []string{loc.City, loc.Region, loc.CountryCode} {
+			if p != "" {
+				parts = append(parts, p)
+			}
+		}
+		if len(parts) > 0 {
+			contact = append(contact, strings.Join(parts, ", "))
+		}
+	}
+	for _, c := range []string{r.Basics.Label, r.Basics.Email, r.Basics.Phone, r.Basics.URL} {
+		if c != "" {
+			contact = append(contact, c)
+		}
+	}
+	for _, p := range r.Basics.Profiles {
+		if p.URL != "" {
+			contact = append(contact, p.URL)
+		} else if p.Username != "" {
+			contact = append(contact, p.Network+": "+p.Username)
+		}
+	}
+	return contact
+}
+
+// outline lays the resume out in template section order.
+func (r *JSONResume) outline() []outlineSection {
+	layout := defaultJSONResumeLayout
+	if r.Meta != nil && r.Meta.Resumectl != nil && len(r.Meta.Resumectl.Sections) > 0 {
+		layout = r.Meta.Resumectl.Sections
+	}
+
+	var sections []outlineSection
+	for _, l := range layout {
+		s := outlineSection{Title: l.Title}
+		switch l.Key {
+		case "summary":
+			if r.Basics.Summary != "" {
+				s.Paragraphs = []string{r.Basics.Summary}
+			}
+		case "work":
+			for _, w := range r.Work {
+				sub := w.Position
+				if w.Location != "" {
+					sub = strings.TrimPrefix(sub+", "+w.Location, ", ")
+				}
+				bullets := w.Highlights
+				if w.Summary != "" {
+					bullets = append([]string{w.Summary}, bullets...)
+				}
+				s.Entries = append(s.Entries, outlineEntry{w.Name, sub, plainDateRange(w.StartDate, w.EndDate), bullets})
+			}
+		case "projects":
+			for _, p := range r.Projects {
+				item := p.Name
+				if p.Description != "" {
+					item += ": " + p.Description
+				}
+				s.Bullets = append(s.Bullets, item)
+				s.Bullets = append(s.Bullets, p.Highlights...)
+			}
+		case "skills":
+			for _, sk := range r.Skills {
+				s.Paragraphs = append(s.Paragraphs, sk.Name+": "+strings.Join(sk.Keywords, ", "))
+			}
+		case "education":
+			for _, e := range r.Education {
+				degree := strings.TrimSpace(e.StudyType + " " + e.Area)
+				s.Entries = append(s.Entries, outlineEntry{e.Institution, degree, plainDateRange(e.StartDate, e.EndDate), nil})
+			}
+		default:
+			s.Bullets = l.Items
+			for _, line := range l.Lines {
+				if isCommentLine(line) {
+					continue
+				}
+				if text := latexPlain(line); text != "" {
+					s.Paragraphs = append(s.Paragraphs, text)
+				}
+			}
+		}
+		if len(s.Paragraphs) > 0 || len(s.Entries) > 0 || len(s.Bullets) > 0 {
+			sections = append(sections, s)
+		}
+	}
+	return sections
+}
+
+func plainDateRange(start, end string) string {
+	return strings.Replace(displayDateRange(start, end), " -- ", " - ", 1)
+}
+
+// renderText writes an ATS-safe plain-text resume: one column, ASCII
+// bullets, headings in capitals.
+func renderText(r *JSONResume) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString(r.Basics.Name + "\n")
+	if contact := r.contactLine(); len(contact) > 0 {
+		b.WriteString(strings.Join(contact, " | ") + "\n")
+	}
+	for _, s := range r.outline() {
+		fmt.Fprintf(&b, "\n%s\n", strings.ToUpper(s.Title))
+		for _, p := range s.Paragraphs {
+			b.WriteString(p + "\n")
+		}
+		for _, e := range s.Entries {
+			line := e.Title
+			if e.Subtitle != "" {
+				line += " - " + e.Subtitle
+			}
+			if e.Dates != "" {
+				line += " (" + e.Dates + ")"
+			}
+			b.WriteString(line + "\n")
+			for _, bullet := range e.Bullets {
+				b.WriteString("- " + bullet + "\n")
+			}
+		}
+		for _, bullet := range s.Bullets {
+			b.WriteString("- " + bullet + "\n")
+		}
+	}
+	return []byte(b.String()), nil
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "<", `\<`, "#", `\#`)
+
+func renderMarkdown(r *JSONResume) ([]byte, error) {
+	md := markdownEscaper.Replace
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", md(r.Basics.Name))
+	if contact := r.contactLine(); len(contact) > 0 {
+		for i, c := range contact {
+			contact[i] = md(c)
+		}
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(contact, " · "))
+	}
+	for _, s := range r.outline() {
+		fmt.Fprintf(&b, "\n## %s\n\n", md(s.Title))
+		for _, p := range s.Paragraphs {
+			b.WriteString(md(p) + "\n\n")
+		}
+		for _, e := range s.Entries {
+			heading := "**" + md(e.Title) + "**"
+			if e.Subtitle != "" {
+				heading += ", " + md(e.Subtitle)
+			}
+			if e.Dates != "" {
+				heading += " — " + md(e.Dates)
+			}
+			b.WriteString("### " + heading + "\n\n")
+			for _, bullet := range e.Bullets {
+				b.WriteString("- " + md(bullet) + "\n")
+			}
+			if len(e.Bullets) > 0 {
+				b.WriteString("\n")
+			}
+		}
+		for _, bullet := range s.Bullets {
+			b.WriteString("- " + md(bullet) + "\n")
+		}
+	}
+	return []byte(strings.TrimRight(b.String(), "\n") + "\n"), nil
+}
+
+var resumeHTMLTemplate = template.Must(template.New("resume").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: Georgia, serif; max-width: 8.5in; margin: 0.6in auto; padding: 0 0.75in; color: #222; line-height: 1.35; }
+h1 { text-align: center; font-size: 1.8em; margin: 0; letter-spacing: 0.05em; }
+.contact { text-align: center; margin: 0.3em 0 1em; }
+h2 { font-size: 1em; text-transform: uppercase; border-bottom: 1px solid #222; margin: 1.2em 0 0.4em; }
+.entry { margin-top: 0.5em; }
+.entry header { display: flex; justify-content: space-between; }
+.entry .sub { font-style: italic; }
+ul { margin: 0.2em 0; padding-left: 1.4em; }
+p { margin: 0.2em 0; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Contact}}<div class="contact">{{range $i, $c := .Contact}}{{if $i}} &middot; {{end}}{{$c}}{{end}}</div>{{end}}
+{{range .Sections}}<section>
+<h2>{{.Title}}</h2>
+{{range .Paragraphs}}<p>{{.}}</p>
+{{end}}{{range .Entries}}<div class="entry">
+<header><span><strong>{{.Title}}</strong>{{if .Subtitle}} <span class="sub">{{.Subtitle}}</span>{{end}}</span><span>{{.Dates}}</span></header>
+{{if .Bullets}}<ul>
+{{range .Bullets}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</div>
+{{end}}{{if .Bullets}}<ul>
+{{range .Bullets}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}</section>
+{{end}}</body>
+</html>
+`))
+
+func renderHTML(r *JSONResume) ([]byte, error) {
+	var b bytes.Buffer
+	err := resumeHTMLTemplate.Execute(&b, map[string]interface{}{
+		"Name":     r.Basics.Name,
+		"Contact":  r.contactLine(),
+		"Sections": r.outline(),
+	})
+	return b.Bytes(), err
+}
+
+const docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+</Types>`
+
+const docxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+</Relationships>`
+
+const docxDocumentRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+
+const docxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">
+<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri"/><w:sz w:val="21"/></w:rPr></w:rPrDefault>
+<w:pPrDefault><w:pPr><w:spacing w:after="40"/></w:pPr></w:pPrDefault></w:docDefaults>
+<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/></w:style>
+<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:pPr><w:jc w:val="center"/></w:pPr><w:rPr><w:b/><w:sz w:val="36"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="60"/><w:pBdr><w:bottom w:val="single" w:sz="4" w:space="1" w:color="auto"/></w:pBdr><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:caps/><w:sz w:val="24"/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:pPr><w:keepNext/><w:spacing w:before="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/></w:rPr></w:style>
+<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:pPr><w:ind w:left="360" w:hanging="220"/></w:pPr></w:style>
+</w:styles>`
+
+// renderDocx writes a minimal WordprocessingML package: built-in heading
+// styles and text bullets, so it also survives ATS parsers.
+func renderDocx(r *JSONResume) ([]byte, error) {
+	var body strings.Builder
+	para := func(style, text string) {
+		body.WriteString("<w:p>")
+		if style != "" {
+			fmt.Fprintf(&body, `<w:pPr><w:pStyle w:val="%s"/></w:pPr>`, style)
+		}
+		body.WriteString(`<w:r><w:t xml:space="preserve">`)
+		xml.EscapeText(&body, []byte(text))
+		body.WriteString("</w:t></w:r></w:p>")
+	}
+
+	para("Title", r.Basics.Name)
+	if contact := r.contactLine(); len(contact) > 0 {
+		body.WriteString(`<w:p><w:pPr><w:jc w:val="center"/></w:pPr><w:r><w:t xml:space="preserve">`)
+		xml.EscapeText(&body, []byte(strings.Join(contact, " | ")))
+		body.WriteString("</w:t></w:r></w:p>")
+	}
+	for _, s := range r.outline() {
+		para("Heading1", s.Title)
+		for _, p := range s.Paragraphs {
+			para("", p)
+		}
+		for _, e := range s.Entries {
+			heading := e.Title
+			if e.Subtitle != "" {
+				heading += ", " + e.Subtitle
+			}
+			if e.Dates != "" {
+				heading += " (" + e.Dates + ")"
+			}
+			para("Heading2", heading)
+			for _, bullet := range e.Bullets {
+				para("ListParagraph", "• "+bullet)
+			}
+		}
+		for _, bullet := range s.Bullets {
+			para("ListParagraph", "• "+bullet)
+		}
+	}
+
+	document := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body.String() +
+		`<w:sectPr><w:pgSz w:w="12240" w:h="15840"/><w:pgMar w:top="864" w:right="1080" w:bottom="864" w:left="1080" w:header="0" w:footer="0" w:gutter="0"/></w:sectPr></w:body></w:document>`
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, part := range []struct{ name, data string }{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxRels},
+		{"word/_rels/document.xml.rels", docxDocumentRels},
+		{"word/styles.xml", docxStyles},
+		{"word/document.xml", document},
+	} {
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: part.name, Method: zip.Deflate, Modified: time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write([]byte(part.data)); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
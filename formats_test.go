@@ -0,0 +1,129 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderFormats(t *testing.T) {
+	doc, err := parseResume(strings.Replace(sampleResume, "Mentored 4 engineers", "Mentored 4 engineers in R&D <fast>", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := jsonResumeFromDoc(doc)
+
+	tests := []struct {
+		format string
+		want   []string
+	}{
+		{"txt", []string{
+			"Jane Doe\nSan Francisco, CA | jane@example.com\n",
+			"\nEXPERIENCE\nAcme Corp - Staff Data Engineer, Remote (Jan 2021 - Present)\n- Built a streaming platform",
+			"- Led migration from Airflow 1 to Airflow 2 across 300 DAGs\n",
+			"- Mentored 4 engineers in R&D <fast>\n",
+			"Data: Kafka, Flink, Spark, dbt, Airflow\n",
+			"State University - B.S. Computer Science (2017)\n",
+		}},
+		{"md", []string{
+			"# Jane Doe\n",
+			"### **Acme Corp**, Staff Data Engineer, Remote — Jan 2021 - Present\n",
+			"- Mentored 4 engineers in R&D \\<fast>\n",
+			"## Technical Strengths\n",
+		}},
+		{"html", []string{
+			"<title>Jane Doe</title>",
+			"<li>Mentored 4 engineers in R&amp;D &lt;fast&gt;</li>",
+			"<h2>Education</h2>",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			out, err := resumeFormats[tt.format](r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(out), w) {
+					t.Errorf("missing %q in:\n%s", w, out)
+				}
+			}
+			if strings.Contains(string(out), `\item`) || strings.Contains(string(out), `\hfill`) {
+				t.Errorf("LaTeX leaked into output:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestRenderDocx(t *testing.T) {
+	doc, err := parseResume(strings.Replace(sampleResume, "Mentored 4 engineers", "Mentored 4 engineers in R&D", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := renderDocx(jsonResumeFromDoc(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+		dec := xml.NewDecoder(bytes.NewReader(b))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/document.xml", "word/styles.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("missing part %s", name)
+		}
+	}
+	for _, want := range []string{"Mentored 4 engineers in R&amp;D", `<w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">Experience`} {
+		if !strings.Contains(parts["word/document.xml"], want) {
+			t.Errorf("document.xml missing %q", want)
+		}
+	}
+
+	again, _ := renderDocx(jsonResumeFromDoc(doc))
+	if !bytes.Equal(data, again) {
+		t.Error("docx output is not deterministic")
+	}
+}
+
+func TestWriteResumeFormats(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := writeResumeFormats(dir, sampleResume, []string{"txt", "pdf"}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+	written, err := writeResumeFormats(dir, sampleResume, []string{"txt", "docx"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(written, ",") != "resume.txt,resume.docx" {
+		t.Errorf("written = %q", written)
+	}
+	for _, f := range written {
+		if _, err := os.Stat(filepath.Join(dir, f)); err != nil {
+			t.Error(err)
+		}
+	}
+}
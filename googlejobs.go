@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -31,7 +32,7 @@ type SerpAPIJob struct {
 	JobID string `json:"job_id"`
 }
 
-func scanGoogleJobs(keywords []string, maxAgeDays int) ([]ScanResult, error) {
+func scanGoogleJobs(ctx context.Context, keywords []string, maxAgeDays int) ([]ScanResult, error) {
 	apiKey := os.Getenv("SERP_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("SERP_API_KEY not set in .env")
@@ -47,27 +48,18 @@ func scanGoogleJobs(keywords []string, maxAgeDays int) ([]ScanResult, error) {
 	params.Set("hl", "en")
 	params.Set("location", "United States")
 
-	if scanLocation != "" {
-		locFilter := strings.ToLower(scanLocation)
-		if strings.Contains(locFilter, "remote") {
-			params.Set("ltype", "1")
-		} else if strings.Contains(locFilter, "los angeles") {
-			params.Set("location", "Los Angeles, CA")
-		} else if strings.Contains(locFilter, "san francisco") || strings.Contains(locFilter, "sf") {
-			params.Set("location", "San Francisco, CA")
-		} else if strings.Contains(locFilter, "new york") || strings.Contains(locFilter, "nyc") {
-			params.Set("location", "New York, NY")
-		} else if strings.Contains(locFilter, "usa") || strings.Contains(locFilter, "us") {
-			params.Set("location", "United States")
-		} else {
-			params.Set("location", scanLocation)
-		}
+	if filters, err := parseLocationFilters(scanLocation); err == nil {
+		applySerpLocation(params, filters)
 	}
 
 	apiURL := "https://serpapi.com/search?" + params.Encode()
 
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, err
+	}
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Get(apiURL)
+	resp, err := limitedDo(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -101,19 +93,53 @@ func scanGoogleJobs(keywords []string, maxAgeDays int) ([]ScanResult, error) {
 		}
 
 		results = append(results, ScanResult{
-			Title:    j.Title,
-			Company:  j.CompanyName,
-			URL:      jobURL,
-			Age:      ageStr,
-			AgeDays:  ageDays,
-			Location: j.Location,
-			Salary:   j.DetectedExtensions.SalaryInfo,
+			Title:       j.Title,
+			Company:     j.CompanyName,
+			URL:         jobURL,
+			Age:         ageStr,
+			AgeDays:     ageDays,
+			Location:    j.Location,
+			Salary:      j.DetectedExtensions.SalaryInfo,
+			Source:      "google",
+			Description: j.Description,
 		})
 	}
 
 	return results, nil
 }
 
+func applySerpLocation(params url.Values, filters []locationFilter) {
+	for _, f := range filters {
+		switch f.kind {
+		case "remote":
+			params.Set("ltype", "1")
+			if name, ok := countryNames[f.value]; ok {
+				params.Set("location", name)
+				params.Set("gl", strings.ToLower(f.value))
+			}
+		case "country":
+			if name, ok := countryNames[f.value]; ok {
+				params.Set("location", name)
+				params.Set("gl", strings.ToLower(f.value))
+			}
+		case "text":
+			loc := parseLocation(f.value)
+			if loc.City != "" && loc.Region != "" {
+				params.Set("location", loc.City+", "+loc.Region)
+			} else if loc.City != "" {
+				params.Set("location", loc.City+", "+countryNames[loc.Country])
+			} else if name, ok := countryNames[loc.Country]; ok {
+				params.Set("location", name)
+			} else {
+				params.Set("location", f.value)
+			}
+			if loc.Country != "" {
+				params.Set("gl", strings.ToLower(loc.Country))
+			}
+		}
+	}
+}
+
 func parsePostedAt(text string) int {
 	text = strings.ToLower(text)
 
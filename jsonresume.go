@@ -0,0 +1,661 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// JSONResume is the subset of the JSON Resume schema (jsonresume.org) that
+// maps onto resume.cls. It is the model both converters go through: LaTeX
+// templates are read into it and written from it. Section titles and order
+// are kept in meta.resumectl so a round trip keeps the layout.
+type JSONResume struct {
+	Basics    JSONResumeBasics      `json:"basics"`
+	Work      []JSONResumeWork      `json:"work,omitempty"`
+	Education []JSONResumeEducation `json:"education,omitempty"`
+	Skills    []JSONResumeSkill     `json:"skills,omitempty"`
+	Projects  []JSONResumeProject   `json:"projects,omitempty"`
+	Meta      *JSONResumeMeta       `json:"meta,omitempty"`
+}
+
+type JSONResumeBasics struct {
+	Name     string              `json:"name"`
+	Label    string              `json:"label,omitempty"`
+	Email    string              `json:"email,omitempty"`
+	Phone    string              `json:"phone,omitempty"`
+	URL      string              `json:"url,omitempty"`
+	Summary  string              `json:"summary,omitempty"`
+	Location *JSONResumeLocation `json:"location,omitempty"`
+	Profiles []JSONResumeProfile `json:"profiles,omitempty"`
+}
+
+type JSONResumeLocation struct {
+	City        string `json:"city,omitempty"`
+	Region      string `json:"region,omitempty"`
+	CountryCode string `json:"countryCode,omitempty"`
+}
+
+type JSONResumeProfile struct {
+	Network  string `json:"network,omitempty"`
+	Username string `json:"username,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type JSONResumeWork struct {
+	Name       string   `json:"name"`
+	Position   string   `json:"position,omitempty"`
+	Location   string   `json:"location,omitempty"`
+	StartDate  string   `json:"startDate,omitempty"`
+	EndDate    string   `json:"endDate,omitempty"`
+	Summary    string   `json:"summary,omitempty"`
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type JSONResumeEducation struct {
+	Institution string `json:"institution"`
+	Area        string `json:"area,omitempty"`
+	StudyType   string `json:"studyType,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+}
+
+type JSONResumeSkill struct {
+	Name     string   `json:"name"`
+	Keywords []string `json:"keywords,omitempty"`
+}
+
+type JSONResumeProject struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Highlights  []string `json:"highlights,omitempty"`
+}
+
+type JSONResumeMeta struct {
+	Resumectl *JSONResumeLayout `json:"resumectl,omitempty"`
+}
+
+// JSONResumeLayout records the LaTeX section order and titles. Sections
+// with no JSON Resume equivalent keep their bullets or raw LaTeX lines.
+type JSONResumeLayout struct {
+	Sections []JSONResumeLayoutSection `json:"sections"`
+}
+
+type JSONResumeLayoutSection struct {
+	Key   string   `json:"key"`
+	Title string   `json:"title"`
+	Items []string `json:"items,omitempty"`
+	Lines []string `json:"lines,omitempty"`
+}
+
+var defaultJSONResumeLayout = []JSONResumeLayoutSection{
+	{Key: "summary", Title: "Summary"},
+	{Key: "work", Title: "Experience"},
+	{Key: "projects", Title: "Projects"},
+	{Key: "skills", Title: "Technical Strengths"},
+	{Key: "education", Title: "Education"},
+}
+
+var (
+	resumeImportLabel string
+	resumeImportForce bool
+	resumeExportOut   string
+)
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Convert resume templates to and from JSON Resume",
+}
+
+func init() {
+	importCmd := &cobra.Command{
+		Use:   "import <resume.json>",
+		Short: "Generate resume.template.<label>.tex from a JSON Resume file",
+		Args:  cobra.ExactArgs(1),
+		Run:   runResumeImport,
+	}
+	importCmd.Flags().StringVar(&resumeImportLabel, "label", "", "Template label, e.g. data-platform (required)")
+	importCmd.Flags().BoolVar(&resumeImportForce, "force", false, "Overwrite an existing template")
+	importCmd.MarkFlagRequired("label")
+
+	exportCmd := &cobra.Command{
+		Use:   "export <template.tex>",
+		Short: "Convert a LaTeX template to JSON Resume",
+		Args:  cobra.ExactArgs(1),
+		Run:   runResumeExport,
+	}
+	exportCmd.Flags().StringVarP(&resumeExportOut, "out", "o", "", "Write JSON to a file instead of stdout")
+
+	resumeCmd.AddCommand(importCmd, exportCmd)
+}
+
+var templateLabelRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+func runResumeImport(cmd *cobra.Command, args []string) {
+	if !templateLabelRe.MatchString(resumeImportLabel) {
+		fmt.Fprintf(os.Stderr, "Invalid label %q: use letters, digits, '.', '_' and '-'\n", resumeImportLabel)
+		os.Exit(1)
+	}
+	data, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	var r JSONResume
+	if err := json.Unmarshal(data, &r); err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	if r.Basics.Name == "" {
+		fmt.Fprintf(os.Stderr, "Error: %s has no basics.name\n", args[0])
+		os.Exit(1)
+	}
+	if dropped := unsupportedSections(data); len(dropped) > 0 {
+		fmt.Fprintf(os.Stderr, "  Warning: resume.cls templates have no place for %s; they were not imported\n", strings.Join(dropped, ", "))
+	}
+
+	out := fmt.Sprintf("resume.template.%s.tex", resumeImportLabel)
+	if _, err := os.Stat(out); err == nil && !resumeImportForce {
+		fmt.Fprintf(os.Stderr, "%s already exists (use --force to overwrite)\n", out)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(out, []byte(r.LaTeX()), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", out, err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s Wrote %s (%d roles, %d skill groups)\n", color.GreenString("✓"), out, len(r.Work), len(r.Skills))
+	fmt.Printf("Register it with: resumectl template add %s\n", out)
+}
+
+func runResumeExport(cmd *cobra.Command, args []string) {
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	doc, err := parseResume(string(src))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error parsing %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	data, _ := json.MarshalIndent(jsonResumeFromDoc(doc), "", "  ")
+	data = append(data, '\n')
+
+	if resumeExportOut == "" {
+		os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(resumeExportOut, data, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", resumeExportOut, err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "%s Wrote %s\n", color.GreenString("✓"), resumeExportOut)
+}
+
+// unsupportedSections lists the top-level JSON Resume sections in data
+// that the LaTeX template has no place for, such as volunteer or awards.
+func unsupportedSections(data []byte) []string {
+	var sections map[string]json.RawMessage
+	if json.Unmarshal(data, &sections) != nil {
+		return nil
+	}
+	var dropped []string
+	for key, value := range sections {
+		switch key {
+		case "$schema", "basics", "work", "education", "skills", "projects", "meta":
+			continue
+		}
+		if v := strings.TrimSpace(string(value)); v != "null" && v != "[]" && v != "{}" && v != `""` {
+			dropped = append(dropped, key)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped
+}
+
+var (
+	emailRe = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	phoneRe = regexp.MustCompile(`^\+?[\d\s().-]{7,}$`)
+	urlRe   = regexp.MustCompile(`^(https?://)?([\w-]+\.)+[a-z]{2,}(/\S*)?$`)
+)
+
+// jsonResumeFromDoc reads a parsed LaTeX template into the JSON Resume
+// model.
+func jsonResumeFromDoc(doc *ResumeDoc) *JSONResume {
+	r := &JSONResume{Basics: JSONResumeBasics{Name: latexPlain(doc.Name)}}
+	for _, line := range doc.Address {
+		for _, part := range strings.Split(line, `\\`) {
+			addContact(&r.Basics, latexPlain(part))
+		}
+	}
+
+	layout := &JSONResumeLayout{}
+	for _, s := range doc.Sections {
+		title := latexPlain(s.Title)
+		key := jsonResumeSection(r, s)
+		entry := JSONResumeLayoutSection{Key: key, Title: title}
+		if key == "custom" {
+			for _, b := range s.Blocks {
+				if b.List != nil {
+					for _, bullet := range b.List.Bullets {
+						entry.Items = append(entry.Items, latexPlain(bullet.Text))
+					}
+				}
+			}
+			if entry.Items == nil {
+				entry.Lines = sectionLines(s)
+			}
+		}
+		layout.Sections = append(layout.Sections, entry)
+	}
+	r.Meta = &JSONResumeMeta{Resumectl: layout}
+	return r
+}
+
+func addContact(b *JSONResumeBasics, part string) {
+	switch {
+	case part == "":
+	case emailRe.MatchString(part) && b.Email == "":
+		b.Email = part
+	case phoneRe.MatchString(part) && b.Phone == "":
+		b.Phone = part
+	case urlRe.MatchString(part):
+		network := ""
+		for _, n := range []string{"linkedin", "github", "gitlab", "twitter"} {
+			if strings.Contains(strings.ToLower(part), n) {
+				network = strings.ToUpper(n[:1]) + n[1:]
+				if n == "linkedin" {
+					network = "LinkedIn"
+				} else if n == "github" {
+					network = "GitHub"
+				} else if n == "gitlab" {
+					network = "GitLab"
+				}
+			}
+		}
+		if network == "" && b.URL == "" {
+			b.URL = part
+			return
+		}
+		b.Profiles = append(b.Profiles, JSONResumeProfile{Network: network, URL: part})
+	case b.Location == nil:
+		loc := &JSONResumeLocation{City: part}
+		if i := strings.LastIndex(part, ","); i > 0 {
+			loc.City, loc.Region = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		b.Location = loc
+	case b.Label == "":
+		b.Label = part
+	}
+}
+
+// jsonResumeSection fills r from one LaTeX section and returns the JSON
+// Resume key it went to, or "custom".
+func jsonResumeSection(r *JSONResume, s *ResumeSection) string {
+	title := strings.ToLower(latexPlain(s.Title))
+	var roles []*ResumeRole
+	var lists []*ResumeList
+	var tables []*SkillTable
+	for _, b := range s.Blocks {
+		switch {
+		case b.Role != nil:
+			roles = append(roles, b.Role)
+		case b.List != nil:
+			lists = append(lists, b.List)
+		case b.Skills != nil:
+			tables = append(tables, b.Skills)
+		}
+	}
+
+	switch {
+	case len(roles) > 0:
+		for _, role := range roles {
+			start, end := parseDateRange(role.Dates)
+			w := JSONResumeWork{
+				Name:      latexPlain(role.Employer),
+				Position:  latexPlain(role.Title),
+				Location:  latexPlain(role.Location),
+				StartDate: start,
+				EndDate:   end,
+			}
+			for _, b := range role.Bullets {
+				if summary, ok := strings.CutPrefix(b.Text, "[]"); ok {
+					if summary = latexPlain(summary); summary != "" && w.Summary == "" {
+						w.Summary = summary
+					}
+					continue
+				}
+				w.Highlights = append(w.Highlights, latexPlain(b.Text))
+			}
+			r.Work = append(r.Work, w)
+		}
+		return "work"
+	case len(tables) > 0:
+		for _, t := range tables {
+			for _, row := range t.Rows {
+				skill := JSONResumeSkill{Name: latexPlain(row.Category)}
+				for _, item := range row.Items {
+					skill.Keywords = append(skill.Keywords, latexPlain(item))
+				}
+				r.Skills = append(r.Skills, skill)
+			}
+		}
+		return "skills"
+	case len(lists) > 0 && strings.Contains(title, "project"):
+		for _, l := range lists {
+			for i, b := range l.Bullets {
+				p := JSONResumeProject{Name: latexPlain(b.Text)}
+				if i := strings.Index(p.Name, ": "); i > 0 {
+					p.Name, p.Description = p.Name[:i], p.Name[i+2:]
+				}
+				// A nested list of highlights sits between this bullet and
+				// the next, so the parser keeps it with the next bullet.
+				after := l.Tail
+				if i+1 < len(l.Bullets) {
+					after = l.Bullets[i+1].lead
+				}
+				p.Highlights = nestedItems(after)
+				r.Projects = append(r.Projects, p)
+			}
+		}
+		return "projects"
+	case len(lists) == 0 && strings.Contains(title, "education"):
+		if ed := parseEducation(sectionLines(s)); len(ed) > 0 {
+			r.Education = append(r.Education, ed...)
+			return "education"
+		}
+	case len(lists) == 0 && (strings.Contains(title, "summary") || strings.Contains(title, "profile") || strings.Contains(title, "about") || strings.Contains(title, "objective")):
+		var text []string
+		for _, l := range sectionLines(s) {
+			if !isCommentLine(l) {
+				text = append(text, l)
+			}
+		}
+		if summary := latexPlain(strings.Join(text, " ")); summary != "" && r.Basics.Summary == "" {
+			r.Basics.Summary = summary
+			return "summary"
+		}
+	}
+	return "custom"
+}
+
+// nestedItems returns the text of the \item lines in a nested list.
+func nestedItems(lines []string) []string {
+	var items []string
+	for _, l := range lines {
+		if m := itemRe.FindStringSubmatch(l); m != nil {
+			text, _ := splitComment(l[len(m[1])+len(`\item`):])
+			items = append(items, latexPlain(text))
+		}
+	}
+	return items
+}
+
+// sectionLines returns a section's plain-text lines, without blank lines.
+func sectionLines(s *ResumeSection) []string {
+	var lines []string
+	for _, b := range s.Blocks {
+		for _, l := range b.render() {
+			if strings.TrimSpace(l) != "" {
+				lines = append(lines, l)
+			}
+		}
+	}
+	return lines
+}
+
+// parseEducation reads entries written as "{\bf School} \hfill {\em 2017} \\"
+// followed by a "B.S. Computer Science" line.
+func parseEducation(lines []string) []JSONResumeEducation {
+	var entries []JSONResumeEducation
+	for _, l := range lines {
+		if isCommentLine(l) {
+			continue
+		}
+		text, _ := splitComment(l)
+		if i := strings.Index(text, `\hfill`); i >= 0 {
+			e := JSONResumeEducation{Institution: latexPlain(text[:i])}
+			e.StartDate, e.EndDate = parseDateRange(strings.TrimSuffix(strings.TrimSpace(text[i+len(`\hfill`):]), `\\`))
+			if e.EndDate == "" && e.StartDate != "" {
+				e.StartDate, e.EndDate = "", e.StartDate
+			}
+			entries = append(entries, e)
+			continue
+		}
+		if len(entries) == 0 {
+			return nil
+		}
+		e := &entries[len(entries)-1]
+		degree := latexPlain(strings.TrimSuffix(strings.TrimSpace(text), `\\`))
+		if e.Area != "" {
+			e.Area += "; " + degree
+			continue
+		}
+		if first, rest, ok := strings.Cut(degree, " "); ok && isDegree(first) {
+			e.StudyType, e.Area = first, rest
+		} else {
+			e.Area = degree
+		}
+	}
+	return entries
+}
+
+func isDegree(s string) bool {
+	if strings.Contains(s, ".") {
+		return true
+	}
+	upper := 0
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			upper++
+		}
+	}
+	return upper >= 2 && len(s) <= 5
+}
+
+var months = []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+
+var (
+	dateRangeSepRe = regexp.MustCompile(`\s*(?:--|–|—|\s-\s|\bto\b)\s*`)
+	monthYearRe    = regexp.MustCompile(`^([A-Za-z]{3})[A-Za-z]*\.?\s+(\d{4})$`)
+	isoDateRe      = regexp.MustCompile(`^(\d{4})(?:-(\d{2}))?(?:-\d{2})?$`)
+)
+
+// parseDateRange turns "Jan 2021 -- Present" into ("2021-01", "").
+func parseDateRange(s string) (start, end string) {
+	s = strings.NewReplacer("---", "--", "~", " ").Replace(latexPlainKeepDashes(s))
+	parts := dateRangeSepRe.Split(strings.TrimSpace(s), 2)
+	start = isoDate(parts[0])
+	if len(parts) == 2 {
+		end = isoDate(parts[1])
+	}
+	return start, end
+}
+
+// latexPlainKeepDashes is latexPlain without collapsing "--", which
+// separates date ranges.
+func latexPlainKeepDashes(s string) string {
+	s = latexCommandRe.ReplaceAllString(s, " ")
+	s = strings.NewReplacer("{", "", "}", "", `\`, " ").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func isoDate(s string) string {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "present", "current", "now", "today":
+		return ""
+	}
+	if m := monthYearRe.FindStringSubmatch(s); m != nil {
+		for i, name := range months {
+			if strings.EqualFold(name, m[1]) {
+				return fmt.Sprintf("%s-%02d", m[2], i+1)
+			}
+		}
+	}
+	return s
+}
+
+// displayDate turns "2021-01" or "2021-01-15" into "Jan 2021".
+func displayDate(s string) string {
+	m := isoDateRe.FindStringSubmatch(s)
+	if m == nil || m[2] == "" {
+		return s
+	}
+	var month int
+	fmt.Sscanf(m[2], "%d", &month)
+	if month < 1 || month > 12 {
+		return s
+	}
+	return months[month-1] + " " + m[1]
+}
+
+func displayDateRange(start, end string) string {
+	switch {
+	case start == "" && end == "":
+		return ""
+	case start == "":
+		return displayDate(end)
+	case end == "":
+		return displayDate(start) + " -- Present"
+	}
+	return displayDate(start) + " -- " + displayDate(end)
+}
+
+// latexEscape escapes text for use in a LaTeX document.
+func latexEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\textbackslash{}`, `&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`, `_`, `\_`,
+		`{`, `\{`, `}`, `\}`, `~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+	).Replace(s)
+}
+
+// LaTeX renders the resume as a template using resume.cls conventions.
+func (r *JSONResume) LaTeX() string {
+	var b strings.Builder
+	b.WriteString("\\documentclass{resume}\n")
+	b.WriteString("\\usepackage[left=0.75in,top=0.6in,right=0.75in,bottom=0.6in]{geometry}\n\n")
+	fmt.Fprintf(&b, "\\name{%s}\n", latexEscape(r.Basics.Name))
+
+	contact := r.contactLine()
+	if len(contact) > 0 {
+		for i, c := range contact {
+			contact[i] = latexEscape(c)
+		}
+		fmt.Fprintf(&b, "\\address{%s}\n", strings.Join(contact, ` \\ `))
+	}
+	b.WriteString("\n\\begin{document}\n")
+
+	layout := defaultJSONResumeLayout
+	if r.Meta != nil && r.Meta.Resumectl != nil && len(r.Meta.Resumectl.Sections) > 0 {
+		layout = r.Meta.Resumectl.Sections
+	}
+	for _, s := range layout {
+		body := r.sectionLaTeX(s)
+		if body == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "\n\\begin{rSection}{%s}\n%s\\end{rSection}\n", latexEscape(s.Title), body)
+	}
+
+	b.WriteString("\n\\end{document}\n")
+	return b.String()
+}
+
+func (r *JSONResume) sectionLaTeX(s JSONResumeLayoutSection) string {
+	var b strings.Builder
+	switch s.Key {
+	case "summary":
+		if r.Basics.Summary != "" {
+			b.WriteString(latexEscape(r.Basics.Summary) + "\n")
+		}
+	case "work":
+		for i, w := range r.Work {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "\\begin{rSubsection}{%s}{%s}{%s}{%s}\n",
+				latexEscape(w.Name), displayDateRange(w.StartDate, w.EndDate), latexEscape(w.Position), latexEscape(w.Location))
+			if w.Summary != "" {
+				fmt.Fprintf(&b, "  \\item[] %s\n", latexEscape(w.Summary))
+			}
+			for _, h := range w.Highlights {
+				fmt.Fprintf(&b, "  \\item %s\n", latexEscape(h))
+			}
+			if w.Summary == "" && len(w.Highlights) == 0 {
+				b.WriteString("  \\item[]\n")
+			}
+			b.WriteString("\\end{rSubsection}\n")
+		}
+	case "projects":
+		if len(r.Projects) == 0 {
+			break
+		}
+		b.WriteString("\\begin{itemize}\n")
+		for _, p := range r.Projects {
+			item := p.Name
+			if p.Description != "" {
+				item += ": " + p.Description
+			}
+			fmt.Fprintf(&b, "  \\item %s\n", latexEscape(item))
+			if len(p.Highlights) > 0 {
+				b.WriteString("  \\begin{itemize}\n")
+				for _, h := range p.Highlights {
+					fmt.Fprintf(&b, "    \\item %s\n", latexEscape(h))
+				}
+				b.WriteString("  \\end{itemize}\n")
+			}
+		}
+		b.WriteString("\\end{itemize}\n")
+	case "skills":
+		if len(r.Skills) > 0 {
+			b.WriteString("\\begin{tabular}{ @{} >{\\bfseries}l @{\\hspace{6ex}} l }\n")
+			for _, s := range r.Skills {
+				var items []string
+				for _, k := range s.Keywords {
+					k = latexEscape(k)
+					if strings.Contains(k, ",") {
+						k = "{" + k + "}"
+					}
+					items = append(items, k)
+				}
+				fmt.Fprintf(&b, "%s & %s \\\\\n", latexEscape(s.Name), strings.Join(items, ", "))
+			}
+			b.WriteString("\\end{tabular}\n")
+		}
+	case "education":
+		for _, e := range r.Education {
+			fmt.Fprintf(&b, "{\\bf %s} \\hfill {\\em %s} \\\\\n", latexEscape(e.Institution), displayDateRange(e.StartDate, e.EndDate))
+			degree := strings.TrimSpace(e.StudyType + " " + e.Area)
+			if degree != "" {
+				b.WriteString(latexEscape(degree) + "\n")
+			}
+		}
+	default:
+		if len(s.Items) > 0 {
+			writeItemize(&b, s.Items)
+		} else {
+			for _, l := range s.Lines {
+				b.WriteString(l + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+func writeItemize(b *strings.Builder, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	b.WriteString("\\begin{itemize}\n")
+	for _, item := range items {
+		fmt.Fprintf(b, "  \\item %s\n", latexEscape(item))
+	}
+	b.WriteString("\\end{itemize}\n")
+}
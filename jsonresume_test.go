@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseDateRange(t *testing.T) {
+	tests := []struct {
+		in, start, end, display string
+	}{
+		{"Jan 2021 -- Present", "2021-01", "", "Jan 2021 -- Present"},
+		{"2017 -- 2020", "2017", "2020", "2017 -- 2020"},
+		{"September 2015 -- Mar 2017", "2015-09", "2017-03", "Sep 2015 -- Mar 2017"},
+		{"Jun 2019 to Dec 2019", "2019-06", "2019-12", "Jun 2019 -- Dec 2019"},
+	}
+	for _, tt := range tests {
+		start, end := parseDateRange(tt.in)
+		if start != tt.start || end != tt.end {
+			t.Errorf("parseDateRange(%q) = %q, %q, want %q, %q", tt.in, start, end, tt.start, tt.end)
+		}
+		if got := displayDateRange(start, end); got != tt.display {
+			t.Errorf("displayDateRange(%q, %q) = %q, want %q", start, end, got, tt.display)
+		}
+	}
+}
+
+func TestJSONResumeFromDoc(t *testing.T) {
+	doc, err := parseResume(sampleResume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := jsonResumeFromDoc(doc)
+
+	if r.Basics.Name != "Jane Doe" || r.Basics.Email != "jane@example.com" || r.Basics.Location == nil || r.Basics.Location.City != "San Francisco" {
+		t.Errorf("basics = %+v", r.Basics)
+	}
+	if len(r.Work) != 2 || r.Work[0].StartDate != "2021-01" || r.Work[0].EndDate != "" || len(r.Work[0].Highlights) != 3 {
+		t.Errorf("work = %+v", r.Work)
+	}
+	if r.Work[0].Highlights[1] != "Led migration from Airflow 1 to Airflow 2 across 300 DAGs" {
+		t.Errorf("multi-line highlight = %q", r.Work[0].Highlights[1])
+	}
+	if len(r.Skills) != 3 || !reflect.DeepEqual(r.Skills[1].Keywords, []string{"Kafka", "Flink", "Spark", "dbt, Airflow"}) {
+		t.Errorf("skills = %+v", r.Skills)
+	}
+	if len(r.Projects) != 1 || r.Projects[0].Name != "resumectl" {
+		t.Errorf("projects = %+v", r.Projects)
+	}
+	want := JSONResumeEducation{Institution: "State University", StudyType: "B.S.", Area: "Computer Science", EndDate: "2017"}
+	if len(r.Education) != 1 || r.Education[0] != want {
+		t.Errorf("education = %+v", r.Education)
+	}
+}
+
+func TestJSONResumeRoundTrip(t *testing.T) {
+	doc, err := parseResume(sampleResume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromLaTeX := jsonResumeFromDoc(doc)
+
+	fromJSON := &JSONResume{
+		Basics: JSONResumeBasics{Name: "Jo Smith", Email: "jo@example.com", Summary: "Data engineer."},
+		Work: []JSONResumeWork{
+			{Name: "Acme", Position: "Engineer", StartDate: "2020-02", Summary: "Ran the data team.", Highlights: []string{"Built Kafka pipelines", "Hired 3 engineers"}},
+			{Name: "Initech", Position: "Analyst", StartDate: "2018", EndDate: "2020", Summary: "Reporting for sales."},
+		},
+		Projects: []JSONResumeProject{
+			{Name: "resumectl", Description: "job hunting CLI", Highlights: []string{"500 stars", "Written in Go"}},
+			{Name: "dotfiles"},
+			{Name: "pgkit", Highlights: []string{"Postgres helpers"}},
+		},
+		Skills:    []JSONResumeSkill{{Name: "Data", Keywords: []string{"Kafka", "Spark"}}},
+		Education: []JSONResumeEducation{{Institution: "State University", StudyType: "B.S.", Area: "Computer Science", EndDate: "2017"}},
+	}
+
+	for _, tt := range []struct {
+		name  string
+		start *JSONResume
+	}{
+		{"LaTeX start", fromLaTeX},
+		{"JSON start", fromJSON},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			latex := tt.start.LaTeX()
+			doc, err := parseResume(latex)
+			if err != nil {
+				t.Fatalf("generated template does not parse: %v\n%s", err, latex)
+			}
+			again := jsonResumeFromDoc(doc)
+			if tt.start.Meta == nil {
+				again.Meta = nil
+			}
+
+			a, _ := json.MarshalIndent(tt.start, "", "  ")
+			b, _ := json.MarshalIndent(again, "", "  ")
+			if string(a) != string(b) {
+				t.Errorf("round trip differs:\n%s\n---\n%s", a, b)
+			}
+			if again.LaTeX() != latex {
+				t.Errorf("LaTeX not stable:\n%s", again.LaTeX())
+			}
+		})
+	}
+}
+
+func TestUnsupportedSections(t *testing.T) {
+	data := `{"$schema": "x", "basics": {"name": "Jo"}, "work": [], "volunteer": [{"organization": "Food bank"}],
+		"awards": [{"title": "Best"}], "languages": [], "interests": null}`
+	if got := unsupportedSections([]byte(data)); !reflect.DeepEqual(got, []string{"awards", "volunteer"}) {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestJSONResumeLaTeXEscapes(t *testing.T) {
+	r := &JSONResume{
+		Basics: JSONResumeBasics{Name: "Jo Smith", Summary: "Cut costs 40% for R&D"},
+		Work:   []JSONResumeWork{{Name: "A_B Corp", Position: "Engineer", StartDate: "2020-02", Highlights: []string{"Saved $1M"}}},
+	}
+	doc, err := parseResume(r.LaTeX())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := jsonResumeFromDoc(doc)
+	if got.Basics.Summary != r.Basics.Summary || got.Work[0].Name != "A_B Corp" || got.Work[0].Highlights[0] != "Saved $1M" {
+		t.Errorf("got %+v %+v", got.Basics, got.Work)
+	}
+}
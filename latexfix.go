@@ -0,0 +1,497 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// latexRepairAttempts bounds how many times a failed compile is fixed and
+// retried.
+const latexRepairAttempts = 3
+
+type latexDiagnostic struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+func (d latexDiagnostic) String() string {
+	if d.Line > 0 {
+		return fmt.Sprintf("line %d: %s", d.Line, d.Message)
+	}
+	return d.Message
+}
+
+var (
+	fileLineErrorRe = regexp.MustCompile(`(?m)^(?:error: )?[^:\n ]*\.tex:(\d+): (.+)$`)
+	texErrorRe      = regexp.MustCompile(`(?m)^! (.+)$`)
+	texLineRe       = regexp.MustCompile(`(?m)^l\.(\d+)`)
+)
+
+// parseLatexLog extracts line-level errors from tectonic or -file-line-error
+// output, falling back to classic "! message ... l.N" TeX log entries.
+func parseLatexLog(log string) []latexDiagnostic {
+	var diags []latexDiagnostic
+	for _, m := range fileLineErrorRe.FindAllStringSubmatch(log, -1) {
+		line, _ := strconv.Atoi(m[1])
+		diags = append(diags, latexDiagnostic{line, strings.TrimSpace(m[2])})
+	}
+	if len(diags) > 0 {
+		return diags
+	}
+
+	errs := texErrorRe.FindAllStringSubmatchIndex(log, -1)
+	for i, m := range errs {
+		d := latexDiagnostic{Message: strings.TrimSuffix(strings.TrimSpace(log[m[2]:m[3]]), ".")}
+		end := len(log)
+		if i+1 < len(errs) {
+			end = errs[i+1][0]
+		}
+		if l := texLineRe.FindStringSubmatch(log[m[1]:end]); l != nil {
+			d.Line, _ = strconv.Atoi(l[1])
+		}
+		diags = append(diags, d)
+	}
+	return diags
+}
+
+// autoFixLatex repairs the mistakes models make most: unescaped special
+// characters and unbalanced braces on the lines TeX complained about, and
+// mismatched \begin/\end pairs. It returns the fixed source and a
+// note per fix.
+func autoFixLatex(latex string, diags []latexDiagnostic) (string, []string) {
+	lines := strings.Split(latex, "\n")
+	flagged := make(map[int]string)
+	for _, d := range diags {
+		if d.Line > 0 && d.Line <= len(lines) {
+			flagged[d.Line-1] = d.Message
+		}
+	}
+
+	var notes []string
+	inBody := false
+	tableDepth := 0
+	for i, line := range lines {
+		if envName(beginRe, line) == "document" {
+			inBody = true
+			continue
+		}
+		switch envName(beginRe, line) {
+		case "tabular", "tabular*", "tabularx":
+			tableDepth++
+		}
+		switch envName(endRe, line) {
+		case "tabular", "tabular*", "tabularx":
+			tableDepth--
+		}
+		if !inBody {
+			continue
+		}
+
+		fixed := line
+		msg, flaggedLine := flagged[i]
+		if flaggedLine {
+			fixed = escapeSpecials(line, tableDepth > 0, strings.Contains(msg, "Extra alignment tab"))
+		}
+		if flaggedLine || (itemRe.MatchString(fixed) && !continues(lines, i)) {
+			fixed = balanceBraces(fixed)
+		}
+		if fixed != line {
+			notes = append(notes, fmt.Sprintf("line %d: %s → %s", i+1, strings.TrimSpace(line), strings.TrimSpace(fixed)))
+			lines[i] = fixed
+		}
+	}
+
+	lines, envNotes := fixEnvironments(lines)
+	return strings.Join(lines, "\n"), append(notes, envNotes...)
+}
+
+// continues reports whether the bullet on line i carries on to the next line.
+func continues(lines []string, i int) bool {
+	if i+1 >= len(lines) {
+		return false
+	}
+	next := strings.TrimSpace(lines[i+1])
+	return next != "" && !strings.HasPrefix(next, "%") && !itemRe.MatchString(lines[i+1]) &&
+		!beginRe.MatchString(lines[i+1]) && !endRe.MatchString(lines[i+1])
+}
+
+// rawArgCommands take a file name, label or URL as their first argument,
+// where special characters are meant literally.
+var rawArgCommands = map[string]bool{
+	"includegraphics": true, "input": true, "include": true, "label": true, "ref": true, "pageref": true,
+	"eqref": true, "cite": true, "url": true, "href": true, "nolinkurl": true, "path": true,
+	"usepackage": true, "documentclass": true, "bibliography": true, "includepdf": true,
+}
+
+// escapeSpecials escapes &, #, _ and a % that follows a digit in text. Math
+// and the file, label and URL arguments of rawArgCommands are left alone;
+// inside tables & is an alignment tab unless extraTabs says the row has too
+// many.
+func escapeSpecials(line string, inTable, extraTabs bool) string {
+	var b strings.Builder
+	inMath := false
+	tabs := 0
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if c == '\\' && i+1 < len(line) {
+			name := line[i+1:]
+			if n := strings.IndexFunc(name, func(r rune) bool { return r < 'a' || r > 'z' }); n >= 0 {
+				name = name[:n]
+			}
+			if rawArgCommands[name] {
+				end := rawArgEnd(line, i+1+len(name))
+				b.WriteString(line[i:end])
+				i = end - 1
+				continue
+			}
+			b.WriteByte(c)
+			b.WriteByte(line[i+1])
+			i++
+			continue
+		}
+		switch {
+		case c == '%' && i > 0 && line[i-1] >= '0' && line[i-1] <= '9':
+			b.WriteString(`\%`)
+			continue
+		case c == '%':
+			b.WriteString(line[i:])
+			return b.String()
+		case c == '$':
+			inMath = !inMath
+		case inMath:
+		case c == '&' && inTable:
+			tabs++
+			if extraTabs && tabs > 1 {
+				b.WriteString(`\&`)
+				continue
+			}
+		case c == '&', c == '#', c == '_':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// rawArgEnd returns the index just past the optional [...] and first {...}
+// argument that start at i, or the end of the line if the argument isn't
+// closed.
+func rawArgEnd(line string, i int) int {
+	for i < len(line) && line[i] == ' ' {
+		i++
+	}
+	if i < len(line) && line[i] == '[' {
+		close := strings.IndexByte(line[i:], ']')
+		if close < 0 {
+			return len(line)
+		}
+		i += close + 1
+	}
+	for i < len(line) && line[i] == ' ' {
+		i++
+	}
+	if i >= len(line) || line[i] != '{' {
+		return i
+	}
+	depth := 0
+	for ; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return len(line)
+}
+
+// balanceBraces drops closing braces with no opener and closes any left
+// open, keeping a trailing comment at the end.
+func balanceBraces(line string) string {
+	text, comment := splitComment(line)
+	var b strings.Builder
+	depth, dropped := 0, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '\\' && i+1 < len(text) {
+			b.WriteByte(c)
+			b.WriteByte(text[i+1])
+			i++
+			continue
+		}
+		if c == '{' {
+			depth++
+		} else if c == '}' {
+			if depth == 0 {
+				dropped = true
+				continue
+			}
+			depth--
+		}
+		b.WriteByte(c)
+	}
+	if depth == 0 && !dropped {
+		return line
+	}
+	out := strings.TrimRight(b.String(), " \t") + strings.Repeat("}", depth)
+	if comment != "" {
+		out += " " + comment
+	}
+	return out
+}
+
+// fixEnvironments closes environments left open and drops \end lines that
+// close nothing.
+func fixEnvironments(lines []string) ([]string, []string) {
+	var out, notes []string
+	var stack []string
+	closeTo := func(n int, at int) {
+		for len(stack) > n {
+			env := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			indent := ""
+			if at < len(lines) {
+				indent = lines[at][:len(lines[at])-len(strings.TrimLeft(lines[at], " \t"))]
+			}
+			out = append(out, indent+`\end{`+env+`}`)
+			notes = append(notes, fmt.Sprintf("line %d: added missing \\end{%s}", at+1, env))
+		}
+	}
+
+	for i, line := range lines {
+		if env := envName(beginRe, line); env != "" {
+			stack = append(stack, env)
+			out = append(out, line)
+			continue
+		}
+		env := envName(endRe, line)
+		if env == "" {
+			out = append(out, line)
+			continue
+		}
+		open := -1
+		for j := len(stack) - 1; j >= 0; j-- {
+			if stack[j] == env {
+				open = j
+				break
+			}
+		}
+		if open < 0 {
+			notes = append(notes, fmt.Sprintf("line %d: removed \\end{%s} with no matching \\begin", i+1, env))
+			continue
+		}
+		closeTo(open+1, i)
+		stack = stack[:open]
+		out = append(out, line)
+	}
+	closeTo(0, len(lines))
+	return out, notes
+}
+
+// compileWithRepair compiles texFile in dir. When the compile fails it
+// parses the log, applies automatic fixes or asks the model for targeted
+// ones, rewrites texFile and tries again. It returns the fixes applied.
+func compileWithRepair(dir, texFile string) ([]string, error) {
+	path := filepath.Join(dir, texFile)
+	var fixes []string
+	for attempt := 0; ; attempt++ {
+		log, err := compileResume(dir, texFile)
+		if err == nil {
+			return fixes, nil
+		}
+
+		if errors.Is(err, exec.ErrNotFound) {
+			return fixes, err
+		}
+		diags := parseLatexLog(log)
+		if attempt >= latexRepairAttempts {
+			return fixes, compileError(err, diags, log)
+		}
+
+		src, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return fixes, readErr
+		}
+		fixed, notes := autoFixLatex(string(src), diags)
+		if fixed == string(src) {
+			if len(diags) == 0 || os.Getenv("ANTHROPIC_API_KEY") == "" {
+				return fixes, compileError(err, diags, log)
+			}
+			var repairErr error
+			fixed, notes, repairErr = repairLatexWithModel(string(src), diags)
+			if repairErr != nil {
+				return fixes, compileError(fmt.Errorf("%v (model repair failed: %v)", err, repairErr), diags, log)
+			}
+			if fixed == string(src) {
+				return fixes, compileError(err, diags, log)
+			}
+		}
+		if err := os.WriteFile(path, []byte(fixed), 0644); err != nil {
+			return fixes, err
+		}
+		fixes = append(fixes, notes...)
+	}
+}
+
+func compileError(err error, diags []latexDiagnostic, log string) error {
+	if len(diags) == 0 {
+		if tail := strings.TrimSpace(log); tail != "" {
+			lines := strings.Split(tail, "\n")
+			if len(lines) > 10 {
+				lines = lines[len(lines)-10:]
+			}
+			return fmt.Errorf("%v\n%s", err, strings.Join(lines, "\n"))
+		}
+		return err
+	}
+	var msgs []string
+	for _, d := range diags {
+		msgs = append(msgs, "  "+d.String())
+	}
+	return fmt.Errorf("%v\n%s", err, strings.Join(msgs, "\n"))
+}
+
+// repairLatexWithModel sends the lines around each diagnostic to the model
+// and applies the replacement lines it returns.
+func repairLatexWithModel(latex string, diags []latexDiagnostic) (string, []string, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return "", nil, fmt.Errorf("ANTHROPIC_API_KEY not set")
+	}
+
+	lines := strings.Split(latex, "\n")
+	shown := make([]bool, len(lines))
+	for _, d := range diags {
+		for i := d.Line - 4; i < d.Line+3; i++ {
+			if i >= 0 && i < len(lines) {
+				shown[i] = true
+			}
+		}
+	}
+	var excerpt strings.Builder
+	for i, show := range shown {
+		if !show {
+			continue
+		}
+		if i > 0 && !shown[i-1] && excerpt.Len() > 0 {
+			excerpt.WriteString("...\n")
+		}
+		fmt.Fprintf(&excerpt, "%d: %s\n", i+1, lines[i])
+	}
+	var errs []string
+	for _, d := range diags {
+		errs = append(errs, d.String())
+	}
+
+	prompt := fmt.Sprintf(`This LaTeX resume fails to compile. Fix ONLY the errors below by rewriting the affected lines. Do not change wording, add content or touch lines that are not broken.
+
+ERRORS:
+%s
+
+SOURCE (line number: text):
+%s
+Respond with ONLY valid JSON (no markdown) mapping line numbers to their full replacement text:
+{"lines": {"42": "  \\item Fixed line"}}`, strings.Join(errs, "\n"), excerpt.String())
+
+	reqBody := map[string]interface{}{
+		"model":      modelName,
+		"max_tokens": 2000,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	jsonBody, _ := json.Marshal(reqBody)
+
+	req, _ := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return "", nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return "", nil, err
+	}
+
+	if len(apiResp.Content) == 0 {
+		return "", nil, fmt.Errorf("empty response")
+	}
+
+	text := strings.TrimSpace(apiResp.Content[0].Text)
+	if strings.HasPrefix(text, "```") {
+		if idx := strings.Index(text, "\n"); idx != -1 {
+			text = text[idx+1:]
+		}
+		if idx := strings.LastIndex(text, "```"); idx != -1 {
+			text = text[:idx]
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	var fix struct {
+		Lines map[string]string `json:"lines"`
+	}
+	if err := json.Unmarshal([]byte(text), &fix); err != nil {
+		return "", nil, fmt.Errorf("parse error: %v\nRaw: %s", err, text)
+	}
+	fixed, notes := applyLineFixes(lines, fix.Lines)
+	return fixed, notes, nil
+}
+
+// applyLineFixes replaces numbered lines, bottom-up so multi-line
+// replacements don't shift the lines still to be replaced.
+func applyLineFixes(lines []string, replacements map[string]string) (string, []string) {
+	byLine := make(map[int]string)
+	var numbers []int
+	for k, v := range replacements {
+		if n, err := strconv.Atoi(strings.TrimSpace(k)); err == nil && n >= 1 && n <= len(lines) {
+			byLine[n] = strings.TrimRight(v, "\n")
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(numbers)))
+
+	out := append([]string(nil), lines...)
+	var notes []string
+	for _, n := range numbers {
+		if byLine[n] == out[n-1] {
+			continue
+		}
+		notes = append([]string{fmt.Sprintf("line %d (model): %s → %s", n, strings.TrimSpace(out[n-1]), strings.TrimSpace(byLine[n]))}, notes...)
+		out = append(out[:n-1], append(strings.Split(byLine[n], "\n"), out[n:]...)...)
+	}
+	return strings.Join(out, "\n"), notes
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseLatexLog(t *testing.T) {
+	tests := []struct {
+		name string
+		log  string
+		want []latexDiagnostic
+	}{
+		{"tectonic",
+			"note: Running TeX ...\nerror: resume.tex:25: Misplaced alignment tab character &\nerror: resume.tex:31: Missing } inserted\nerror: halted on potentially-recoverable error as specified\n",
+			[]latexDiagnostic{{25, "Misplaced alignment tab character &"}, {31, "Missing } inserted"}}},
+		{"tex log",
+			"(./resume.cls)\n! Undefined control sequence.\n<recently read> \\itme\n\nl.12 \\itme\n            Built things\n! Emergency stop.\n",
+			[]latexDiagnostic{{12, "Undefined control sequence"}, {0, "Emergency stop"}}},
+		{"no errors", "warning: Overfull \\hbox\n", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLatexLog(tt.log); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeSpecials(t *testing.T) {
+	tests := []struct {
+		in        string
+		table     bool
+		extraTabs bool
+		want      string
+	}{
+		{`\item R&D for C# services_v2`, false, false, `\item R\&D for C\# services\_v2`},
+		{`\item Cut costs 40% % keep this comment_x`, false, false, `\item Cut costs 40\% % keep this comment_x`},
+		{`\item Already 40\% and \& fine`, false, false, `\item Already 40\% and \& fine`},
+		{`\item Math $O(n_1)$ stays`, false, false, `\item Math $O(n_1)$ stays`},
+		{`\item \href{https://x.io/a_b}{R&D link}`, false, false, `\item \href{https://x.io/a_b}{R\&D link}`},
+		{`\item See \url{https://x.io/?a=1&b_2} for C#`, false, false, `\item See \url{https://x.io/?a=1&b_2} for C\#`},
+		{`\includegraphics[width=1in]{logo_v2.png} R&D \label{fig:a_b}`, false, false, `\includegraphics[width=1in]{logo_v2.png} R\&D \label{fig:a_b}`},
+		{`\input{sections/work_history}`, false, false, `\input{sections/work_history}`},
+		{`Data & Kafka, Spark \\`, true, false, `Data & Kafka, Spark \\`},
+		{`Data & Kafka & Spark \\`, true, true, `Data & Kafka \& Spark \\`},
+	}
+	for _, tt := range tests {
+		if got := escapeSpecials(tt.in, tt.table, tt.extraTabs); got != tt.want {
+			t.Errorf("escapeSpecials(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBalanceBraces(t *testing.T) {
+	tests := map[string]string{
+		`\item Built \textbf{Go services`:      `\item Built \textbf{Go services}`,
+		`\item Built Go} services`:             `\item Built Go services`,
+		`\item \textbf{ok} \{literal % note {`: `\item \textbf{ok} \{literal % note {`,
+		`\item Built \emph{fast % note`:        `\item Built \emph{fast} % note`,
+		`\item \textbf{balanced} already`:      `\item \textbf{balanced} already`,
+	}
+	for in, want := range tests {
+		if got := balanceBraces(in); got != want {
+			t.Errorf("balanceBraces(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFixEnvironments(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    string
+		want  string
+		notes int
+	}{
+		{"balanced", "\\begin{a}\n\\begin{b}\n\\end{b}\n\\end{a}", "\\begin{a}\n\\begin{b}\n\\end{b}\n\\end{a}", 0},
+		{"inner left open", "\\begin{a}\n  \\begin{itemize}\n  \\item x\n\\end{a}", "\\begin{a}\n  \\begin{itemize}\n  \\item x\n\\end{itemize}\n\\end{a}", 1},
+		{"stray end", "\\begin{a}\n\\end{itemize}\n\\end{a}", "\\begin{a}\n\\end{a}", 1},
+		{"unclosed at eof", "\\begin{a}\nx", "\\begin{a}\nx\n\\end{a}", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notes := fixEnvironments(strings.Split(tt.in, "\n"))
+			if strings.Join(got, "\n") != tt.want || len(notes) != tt.notes {
+				t.Errorf("got %q (notes %q), want %q", strings.Join(got, "\n"), notes, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoFixLatex(t *testing.T) {
+	if got, notes := autoFixLatex(sampleResume, nil); got != sampleResume || len(notes) > 0 {
+		t.Fatalf("valid resume changed: %q", notes)
+	}
+
+	broken := strings.NewReplacer(
+		"Designed the warehouse schema in Snowflake", "Designed the R&D warehouse in \\textbf{Snowflake",
+		"\\end{itemize}\n\\end{rSubsection}", "\\end{rSubsection}",
+	).Replace(sampleResume)
+	if got, _ := autoFixLatex(broken, nil); !strings.Contains(got, `Designed the R&D warehouse in \textbf{Snowflake}`) {
+		t.Errorf("line the log didn't flag was escaped:\n%s", got)
+	}
+
+	line := strings.Count(broken[:strings.Index(broken, "R&D")], "\n") + 1
+	got, notes := autoFixLatex(broken, []latexDiagnostic{{line, "Misplaced alignment tab character &"}})
+	if !strings.Contains(got, `Designed the R\&D warehouse in \textbf{Snowflake}`) {
+		t.Errorf("bullet not fixed:\n%s", got)
+	}
+	if !strings.Contains(got, "\\end{itemize}\n\\end{rSubsection}") {
+		t.Errorf("itemize not closed:\n%s", got)
+	}
+	if len(notes) != 2 {
+		t.Errorf("notes = %q", notes)
+	}
+	if again, more := autoFixLatex(got, []latexDiagnostic{{line, "Misplaced alignment tab character &"}}); again != got || len(more) > 0 {
+		t.Errorf("second pass changed the source: %q", more)
+	}
+}
+
+func TestApplyLineFixes(t *testing.T) {
+	lines := []string{"a", "b & c", "d", "e"}
+	got, notes := applyLineFixes(lines, map[string]string{"2": `b \& c`, "4": "e1\ne2", "9": "out of range", "1": "a"})
+	if got != "a\nb \\& c\nd\ne1\ne2" {
+		t.Errorf("got %q", got)
+	}
+	if len(notes) != 2 || !strings.HasPrefix(notes[0], "line 2") {
+		t.Errorf("notes = %q", notes)
+	}
+}
+
+func TestAdoptLatexRepairs(t *testing.T) {
+	dir := t.TempDir()
+	repaired := strings.NewReplacer(
+		"{Staff Data Engineer}{Remote} % resumectl:lock", "{Principal Engineer}{Remote}",
+		"Mentored 4 engineers", "Mentored 40 engineers",
+	).Replace(lockedResume)
+	if err := os.WriteFile(filepath.Join(dir, "resume.tex"), []byte(repaired), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := &MatchResult{TailoredLatex: lockedResume}
+	compiled, err := adoptLatexRepairs(dir, lockedResume, result, []string{"line 12: model rewrote the line"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if compiled != repaired {
+		t.Error("compiled source should be the repaired file")
+	}
+	if !strings.Contains(result.TailoredLatex, "{Staff Data Engineer}{Remote} % resumectl:lock") {
+		t.Errorf("locked line not restored:\n%s", result.TailoredLatex)
+	}
+	if len(result.LockViolations) != 1 || len(result.LatexFixes) != 1 {
+		t.Errorf("violations = %v, fixes = %v", result.LockViolations, result.LatexFixes)
+	}
+	found := false
+	for _, f := range result.Drift {
+		found = found || strings.Contains(f.Text, "Mentored 40 engineers")
+	}
+	if !found {
+		t.Errorf("repair drift not detected: %v", result.Drift)
+	}
+}
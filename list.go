@@ -10,6 +10,12 @@ import (
 )
 
 func runList(cmd *cobra.Command, args []string) {
+	sortBy, _ := cmd.Flags().GetString("sort")
+	if sortBy != "" && sortBy != "salary" && sortBy != "score" {
+		fmt.Fprintf(os.Stderr, "Unknown sort: %s\nAvailable: salary, score\n", sortBy)
+		os.Exit(1)
+	}
+
 	if err := InitDB(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error initializing database: %v\n", err)
 		os.Exit(1)
@@ -18,7 +24,7 @@ func runList(cmd *cobra.Command, args []string) {
 	status, _ := cmd.Flags().GetString("status")
 	minScore, _ := cmd.Flags().GetInt("min-score")
 
-	jobs, err := ListJobs(status, minScore)
+	jobs, err := ListJobs(status, minScore, sortBy)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error listing jobs: %v\n", err)
 		os.Exit(1)
@@ -29,8 +35,8 @@ func runList(cmd *cobra.Command, args []string) {
 		return
 	}
 
-	fmt.Printf("%-4s %-20s %-30s %-6s %-10s\n", "ID", "Company", "Title", "Score", "Status")
-	fmt.Println(strings.Repeat("-", 75))
+	fmt.Printf("%-4s %-20s %-30s %-6s %-14s %-10s\n", "ID", "Company", "Title", "Score", "Salary", "Status")
+	fmt.Println(strings.Repeat("-", 90))
 
 	for _, j := range jobs {
 		title := j.Title
@@ -51,6 +57,11 @@ func runList(cmd *cobra.Command, args []string) {
 			scoreStr = color.RedString("%d", j.Score)
 		}
 
-		fmt.Printf("%-4d %-20s %-30s %-6s %-10s\n", j.ID, company, title, scoreStr, j.Status)
+		salary := j.Pay.String()
+		if salary == "" {
+			salary = "-"
+		}
+
+		fmt.Printf("%-4d %-20s %-30s %-6s %-14s %-10s\n", j.ID, company, title, scoreStr, salary, j.Status)
 	}
 }
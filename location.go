@@ -0,0 +1,525 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type JobLocation struct {
+	Raw         string
+	City        string
+	Region      string
+	Country     string
+	Remote      bool
+	Hybrid      bool
+	RemoteScope []string
+	TZMin       float64
+	TZMax       float64
+	HasTZ       bool
+}
+
+var usStates = map[string]string{
+	"AL": "alabama", "AK": "alaska", "AZ": "arizona", "AR": "arkansas", "CA": "california",
+	"CO": "colorado", "CT": "connecticut", "DE": "delaware", "FL": "florida", "GA": "georgia",
+	"HI": "hawaii", "ID": "idaho", "IL": "illinois", "IN": "indiana", "IA": "iowa",
+	"KS": "kansas", "KY": "kentucky", "LA": "louisiana", "ME": "maine", "MD": "maryland",
+	"MA": "massachusetts", "MI": "michigan", "MN": "minnesota", "MS": "mississippi", "MO": "missouri",
+	"MT": "montana", "NE": "nebraska", "NV": "nevada", "NH": "new hampshire", "NJ": "new jersey",
+	"NM": "new mexico", "NY": "new york", "NC": "north carolina", "ND": "north dakota", "OH": "ohio",
+	"OK": "oklahoma", "OR": "oregon", "PA": "pennsylvania", "RI": "rhode island", "SC": "south carolina",
+	"SD": "south dakota", "TN": "tennessee", "TX": "texas", "UT": "utah", "VT": "vermont",
+	"VA": "virginia", "WA": "washington", "WV": "west virginia", "WI": "wisconsin", "WY": "wyoming",
+	"DC": "district of columbia",
+}
+
+var caProvinces = map[string]string{
+	"AB": "alberta", "BC": "british columbia", "MB": "manitoba", "NB": "new brunswick",
+	"NL": "newfoundland", "NS": "nova scotia", "ON": "ontario", "PE": "prince edward island",
+	"QC": "quebec", "SK": "saskatchewan",
+}
+
+var countryAliases = []struct {
+	code    string
+	aliases []string
+}{
+	{"US", []string{"united states", "usa", "u.s.a.", "u.s.", "us"}},
+	{"CA", []string{"canada"}},
+	{"GB", []string{"united kingdom", "uk", "great britain", "england", "scotland"}},
+	{"IE", []string{"ireland"}},
+	{"DE", []string{"germany", "deutschland"}},
+	{"FR", []string{"france"}},
+	{"NL", []string{"netherlands", "holland"}},
+	{"ES", []string{"spain"}},
+	{"PT", []string{"portugal"}},
+	{"IT", []string{"italy"}},
+	{"CH", []string{"switzerland"}},
+	{"SE", []string{"sweden"}},
+	{"PL", []string{"poland"}},
+	{"AE", []string{"united arab emirates", "uae"}},
+	{"IL", []string{"israel"}},
+	{"IN", []string{"india"}},
+	{"SG", []string{"singapore"}},
+	{"JP", []string{"japan"}},
+	{"AU", []string{"australia"}},
+	{"BR", []string{"brazil"}},
+	{"MX", []string{"mexico"}},
+	{"AR", []string{"argentina"}},
+}
+
+var countryNames = map[string]string{
+	"US": "United States", "CA": "Canada", "GB": "United Kingdom", "IE": "Ireland", "DE": "Germany",
+	"FR": "France", "NL": "Netherlands", "ES": "Spain", "PT": "Portugal", "IT": "Italy",
+	"CH": "Switzerland", "SE": "Sweden", "PL": "Poland", "AE": "United Arab Emirates", "IL": "Israel",
+	"IN": "India", "SG": "Singapore", "JP": "Japan", "AU": "Australia", "BR": "Brazil",
+	"MX": "Mexico", "AR": "Argentina",
+}
+
+var countryRegions = map[string][]string{
+	"US": {"AMERICAS", "NA"}, "CA": {"AMERICAS", "NA"},
+	"MX": {"AMERICAS", "LATAM"}, "BR": {"AMERICAS", "LATAM"}, "AR": {"AMERICAS", "LATAM"},
+	"GB": {"EMEA", "EUROPE"}, "CH": {"EMEA", "EUROPE"},
+	"IE": {"EMEA", "EUROPE", "EU"}, "DE": {"EMEA", "EUROPE", "EU"}, "FR": {"EMEA", "EUROPE", "EU"},
+	"NL": {"EMEA", "EUROPE", "EU"}, "ES": {"EMEA", "EUROPE", "EU"}, "PT": {"EMEA", "EUROPE", "EU"},
+	"IT": {"EMEA", "EUROPE", "EU"}, "SE": {"EMEA", "EUROPE", "EU"}, "PL": {"EMEA", "EUROPE", "EU"},
+	"AE": {"EMEA"}, "IL": {"EMEA"},
+	"IN": {"APAC"}, "SG": {"APAC"}, "JP": {"APAC"}, "AU": {"APAC"},
+}
+
+// Approximate UTC offsets used when a posting names a country but no timezone.
+var countryTZ = map[string][2]float64{
+	"US": {-10, -5}, "CA": {-8, -3.5}, "MX": {-8, -5}, "BR": {-5, -2}, "AR": {-3, -3},
+	"GB": {0, 1}, "IE": {0, 1}, "PT": {0, 1}, "DE": {1, 2}, "FR": {1, 2}, "NL": {1, 2},
+	"ES": {1, 2}, "IT": {1, 2}, "CH": {1, 2}, "SE": {1, 2}, "PL": {1, 2},
+	"IL": {2, 3}, "AE": {4, 4}, "IN": {5.5, 5.5}, "SG": {8, 8}, "JP": {9, 9}, "AU": {8, 11},
+}
+
+var regionAliases = []struct {
+	region  string
+	aliases []string
+}{
+	{"WORLDWIDE", []string{"worldwide", "anywhere", "global", "any location"}},
+	{"EMEA", []string{"emea"}},
+	{"EU", []string{"eu only", "european union"}},
+	{"EUROPE", []string{"europe", "eu"}},
+	{"APAC", []string{"apac", "asia pacific", "asia"}},
+	{"LATAM", []string{"latam", "latin america", "south america"}},
+	{"NA", []string{"north america"}},
+	{"AMERICAS", []string{"americas"}},
+}
+
+type knownCity struct {
+	name  string
+	place [3]string // city, region, country
+}
+
+var knownCities = []knownCity{
+	{"new york", [3]string{"New York", "NY", "US"}},
+	{"nyc", [3]string{"New York", "NY", "US"}},
+	{"brooklyn", [3]string{"New York", "NY", "US"}},
+	{"san francisco", [3]string{"San Francisco", "CA", "US"}},
+	{"sf", [3]string{"San Francisco", "CA", "US"}},
+	{"bay area", [3]string{"San Francisco", "CA", "US"}},
+	{"los angeles", [3]string{"Los Angeles", "CA", "US"}},
+	{"san diego", [3]string{"San Diego", "CA", "US"}},
+	{"san jose", [3]string{"San Jose", "CA", "US"}},
+	{"palo alto", [3]string{"Palo Alto", "CA", "US"}},
+	{"seattle", [3]string{"Seattle", "WA", "US"}},
+	{"portland", [3]string{"Portland", "OR", "US"}},
+	{"austin", [3]string{"Austin", "TX", "US"}},
+	{"dallas", [3]string{"Dallas", "TX", "US"}},
+	{"houston", [3]string{"Houston", "TX", "US"}},
+	{"denver", [3]string{"Denver", "CO", "US"}},
+	{"chicago", [3]string{"Chicago", "IL", "US"}},
+	{"miami", [3]string{"Miami", "FL", "US"}},
+	{"tampa", [3]string{"Tampa", "FL", "US"}},
+	{"atlanta", [3]string{"Atlanta", "GA", "US"}},
+	{"boston", [3]string{"Boston", "MA", "US"}},
+	{"washington", [3]string{"Washington", "DC", "US"}},
+	{"toronto", [3]string{"Toronto", "ON", "CA"}},
+	{"vancouver", [3]string{"Vancouver", "BC", "CA"}},
+	{"montreal", [3]string{"Montreal", "QC", "CA"}},
+	{"london", [3]string{"London", "", "GB"}},
+	{"dublin", [3]string{"Dublin", "", "IE"}},
+	{"berlin", [3]string{"Berlin", "", "DE"}},
+	{"munich", [3]string{"Munich", "", "DE"}},
+	{"paris", [3]string{"Paris", "", "FR"}},
+	{"amsterdam", [3]string{"Amsterdam", "", "NL"}},
+	{"lisbon", [3]string{"Lisbon", "", "PT"}},
+	{"madrid", [3]string{"Madrid", "", "ES"}},
+	{"barcelona", [3]string{"Barcelona", "", "ES"}},
+	{"zurich", [3]string{"Zurich", "", "CH"}},
+	{"tel aviv", [3]string{"Tel Aviv", "", "IL"}},
+	{"dubai", [3]string{"Dubai", "", "AE"}},
+	{"bangalore", [3]string{"Bangalore", "", "IN"}},
+	{"bengaluru", [3]string{"Bangalore", "", "IN"}},
+	{"singapore", [3]string{"Singapore", "", "SG"}},
+	{"tokyo", [3]string{"Tokyo", "", "JP"}},
+	{"sydney", [3]string{"Sydney", "", "AU"}},
+	{"sao paulo", [3]string{"Sao Paulo", "", "BR"}},
+	{"mexico city", [3]string{"Mexico City", "", "MX"}},
+	{"buenos aires", [3]string{"Buenos Aires", "", "AR"}},
+}
+
+var knownCitiesLongestFirst = func() []knownCity {
+	cities := append([]knownCity(nil), knownCities...)
+	sort.SliceStable(cities, func(i, j int) bool { return len(cities[i].name) > len(cities[j].name) })
+	return cities
+}()
+
+// usStateNames lists the states by name, longest first, so "west virginia"
+// is tried before "virginia".
+var usStateNames = func() []struct{ code, name string } {
+	var names []struct{ code, name string }
+	for code, name := range usStates {
+		names = append(names, struct{ code, name string }{code, name})
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(names[i].name) != len(names[j].name) {
+			return len(names[i].name) > len(names[j].name)
+		}
+		return names[i].name < names[j].name
+	})
+	return names
+}()
+
+var namedTimezones = map[string]float64{
+	"PST": -8, "PDT": -7, "PT": -8, "MST": -7, "MDT": -6,
+	"CST": -6, "CDT": -5, "EST": -5, "EDT": -4, "ET": -5,
+	"GMT": 0, "UTC": 0, "BST": 1, "CET": 1, "CEST": 2, "EET": 2, "IST": 5.5, "SGT": 8, "JST": 9, "AEST": 10,
+}
+
+var (
+	remotePattern   = regexp.MustCompile(`(?i)\b(remote|anywhere|work from home|wfh|distributed|fully remote)\b`)
+	hybridPattern   = regexp.MustCompile(`(?i)\bhybrid\b`)
+	utcOffset       = regexp.MustCompile(`(?i)\b(?:UTC|GMT)\s*([+\-−–])\s*(\d{1,2})(?::?(\d{2}))?`)
+	utcPlusMinus    = regexp.MustCompile(`(?i)\b(?:UTC|GMT)\s*([+\-−–])\s*(\d{1,2})(?::?(\d{2}))?\s*(?:±|\+/-)\s*(\d{1,2})`)
+	namedTZPattern  = regexp.MustCompile(`\b(PST|PDT|PT|MST|MDT|CST|CDT|EST|EDT|ET|GMT|UTC|BST|CET|CEST|EET|IST|SGT|JST|AEST)\b`)
+	regionCodeAfter = regexp.MustCompile(`,\s*([A-Z]{2})\b`)
+	tzRangeFilter   = regexp.MustCompile(`(?i)^(?:utc|gmt)?\s*([+\-−]?\d{1,2}(?:[:.]\d{1,2})?)\s*\.\.\s*(?:utc|gmt)?\s*([+\-−]?\d{1,2}(?:[:.]\d{1,2})?)$`)
+)
+
+func parseLocation(text string) JobLocation {
+	loc := JobLocation{Raw: strings.TrimSpace(text)}
+	lower := " " + strings.ToLower(loc.Raw) + " "
+	lower = strings.NewReplacer("(", " ", ")", " ", "/", " ", "|", " ", ";", " ").Replace(lower)
+
+	loc.Remote = remotePattern.MatchString(loc.Raw)
+	loc.Hybrid = hybridPattern.MatchString(loc.Raw)
+
+	for _, r := range regionAliases {
+		for _, a := range r.aliases {
+			if containsWord(lower, a) && !containsString(loc.RemoteScope, r.region) {
+				loc.RemoteScope = append(loc.RemoteScope, r.region)
+				break
+			}
+		}
+	}
+	if containsString(loc.RemoteScope, "WORLDWIDE") {
+		loc.Remote = true
+	}
+
+	if m := regionCodeAfter.FindStringSubmatch(loc.Raw); m != nil {
+		if _, ok := usStates[m[1]]; ok {
+			loc.Region, loc.Country = m[1], "US"
+		} else if _, ok := caProvinces[m[1]]; ok {
+			loc.Region, loc.Country = m[1], "CA"
+		}
+	}
+
+	for _, c := range countryAliases {
+		matched := false
+		for _, a := range c.aliases {
+			// Two-letter aliases only count in upper case ("US", not "us" in prose).
+			if len(a) <= 2 {
+				if containsWord(" "+loc.Raw+" ", strings.ToUpper(a)) {
+					matched = true
+				}
+			} else if containsWord(lower, a) {
+				matched = true
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		if loc.Remote {
+			if !containsString(loc.RemoteScope, c.code) {
+				loc.RemoteScope = append(loc.RemoteScope, c.code)
+			}
+		}
+		if loc.Country == "" {
+			loc.Country = c.code
+		}
+	}
+
+	// The city mentioned first wins; at the same position the longer name
+	// does, since names are tried longest first.
+	var city *[3]string
+	cityAt := -1
+	for _, c := range knownCitiesLongestFirst {
+		pos := wordIndex(lower, c.name)
+		if pos < 0 || (cityAt >= 0 && pos >= cityAt) {
+			continue
+		}
+		if len(c.name) <= 3 && !containsWord(" "+loc.Raw+" ", strings.ToUpper(c.name)) {
+			continue
+		}
+		city, cityAt = &c.place, pos
+	}
+	if city != nil {
+		loc.City = city[0]
+		if loc.Region == "" {
+			loc.Region = city[1]
+		}
+		if loc.Country == "" {
+			loc.Country = city[2]
+		}
+	}
+
+	// lower starts with a space, so a state named first is at 1.
+	stateAt := -1
+	if loc.Country == "" {
+		for _, st := range usStateNames {
+			if st.name == "washington" || st.name == "georgia" {
+				continue
+			}
+			if pos := wordIndex(lower, st.name); pos >= 0 && (stateAt < 0 || pos < stateAt) {
+				stateAt = pos
+				loc.Region, loc.Country = st.code, "US"
+			}
+		}
+	}
+
+	if loc.City == "" && !loc.Remote && stateAt != 1 {
+		first := strings.TrimSpace(strings.Split(loc.Raw, ",")[0])
+		if _, isState := usStates[strings.ToUpper(first)]; first != "" && !isState && resolveArea(first) == "" {
+			loc.City = first
+		}
+	}
+
+	loc.parseTimezones()
+	return loc
+}
+
+func (loc *JobLocation) parseTimezones() {
+	var offsets []float64
+	if m := utcPlusMinus.FindStringSubmatch(loc.Raw); m != nil {
+		center := offsetValue(m[1], m[2], m[3])
+		spread, _ := strconv.Atoi(m[4])
+		loc.TZMin, loc.TZMax, loc.HasTZ = center-float64(spread), center+float64(spread), true
+		return
+	}
+	for _, m := range utcOffset.FindAllStringSubmatch(loc.Raw, -1) {
+		offsets = append(offsets, offsetValue(m[1], m[2], m[3]))
+	}
+	for _, m := range namedTZPattern.FindAllString(loc.Raw, -1) {
+		if (m == "UTC" || m == "GMT") && utcOffset.MatchString(loc.Raw) {
+			continue
+		}
+		offsets = append(offsets, namedTimezones[m])
+	}
+	if len(offsets) == 0 {
+		return
+	}
+	loc.TZMin, loc.TZMax, loc.HasTZ = offsets[0], offsets[0], true
+	for _, o := range offsets[1:] {
+		if o < loc.TZMin {
+			loc.TZMin = o
+		}
+		if o > loc.TZMax {
+			loc.TZMax = o
+		}
+	}
+}
+
+func offsetValue(sign, hours, minutes string) float64 {
+	h, _ := strconv.Atoi(hours)
+	v := float64(h)
+	if minutes != "" {
+		m, _ := strconv.Atoi(minutes)
+		v += float64(m) / 60
+	}
+	if sign != "+" {
+		v = -v
+	}
+	return v
+}
+
+// Unrestricted remote postings are treated as open to everyone.
+func (loc JobLocation) openTo(area string) bool {
+	if loc.Country == area || containsString(loc.RemoteScope, area) || containsString(countryRegions[loc.Country], area) {
+		return true
+	}
+	if !loc.Remote {
+		return false
+	}
+	if len(loc.RemoteScope) == 0 || containsString(loc.RemoteScope, "WORLDWIDE") {
+		return true
+	}
+	for _, scope := range loc.RemoteScope {
+		if containsString(countryRegions[area], scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (loc JobLocation) timezoneWindow() (float64, float64, bool) {
+	if loc.HasTZ {
+		return loc.TZMin, loc.TZMax, true
+	}
+	if loc.Remote && (len(loc.RemoteScope) == 0 || containsString(loc.RemoteScope, "WORLDWIDE")) {
+		return -12, 14, true
+	}
+	if loc.Remote && len(loc.RemoteScope) > 0 {
+		if tz, ok := countryTZ[loc.RemoteScope[0]]; ok {
+			return tz[0], tz[1], true
+		}
+	}
+	if tz, ok := countryTZ[loc.Country]; ok {
+		return tz[0], tz[1], true
+	}
+	return 0, 0, false
+}
+
+type locationFilter struct {
+	kind  string
+	value string
+	tzMin float64
+	tzMax float64
+}
+
+func parseLocationFilters(expr string) ([]locationFilter, error) {
+	var filters []locationFilter
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kind, value, hasKind := strings.Cut(part, ":")
+		kind = strings.ToLower(strings.TrimSpace(kind))
+		value = strings.TrimSpace(value)
+		if !hasKind {
+			switch kind {
+			case "remote":
+				filters = append(filters, locationFilter{kind: "remote"})
+			case "us", "usa":
+				filters = append(filters, locationFilter{kind: "country", value: "US"})
+			default:
+				filters = append(filters, locationFilter{kind: "text", value: strings.ToLower(part)})
+			}
+			continue
+		}
+
+		switch kind {
+		case "remote", "country", "region":
+			code := resolveArea(value)
+			if code == "" {
+				return nil, fmt.Errorf("unknown %s %q", kind, value)
+			}
+			filters = append(filters, locationFilter{kind: kind, value: code})
+		case "tz":
+			m := tzRangeFilter.FindStringSubmatch(value)
+			if m == nil {
+				return nil, fmt.Errorf("invalid timezone window %q (want e.g. tz:UTC-8..UTC-5)", value)
+			}
+			lo, hi := parseOffset(m[1]), parseOffset(m[2])
+			if lo > hi {
+				lo, hi = hi, lo
+			}
+			filters = append(filters, locationFilter{kind: "tz", tzMin: lo, tzMax: hi})
+		default:
+			return nil, fmt.Errorf("unknown location filter %q (use remote, remote:<area>, country:<code>, region:<name>, tz:<from>..<to>)", kind)
+		}
+	}
+	return filters, nil
+}
+
+func resolveArea(value string) string {
+	upper := strings.ToUpper(value)
+	if _, ok := countryNames[upper]; ok {
+		return upper
+	}
+	lower := strings.ToLower(value)
+	for _, c := range countryAliases {
+		if containsString(c.aliases, lower) {
+			return c.code
+		}
+	}
+	for _, r := range regionAliases {
+		if r.region == upper || containsString(r.aliases, lower) {
+			return r.region
+		}
+	}
+	return ""
+}
+
+func parseOffset(s string) float64 {
+	s = strings.Replace(s, "−", "-", 1)
+	sign := "+"
+	if strings.HasPrefix(s, "-") {
+		sign = "-"
+	}
+	s = strings.TrimLeft(s, "+-")
+	hours, minutes, _ := strings.Cut(strings.Replace(s, ".", ":", 1), ":")
+	return offsetValue(sign, hours, minutes)
+}
+
+func (f locationFilter) matches(loc JobLocation) bool {
+	switch f.kind {
+	case "remote":
+		if !loc.Remote {
+			return false
+		}
+		return f.value == "" || loc.openTo(f.value)
+	case "country", "region":
+		return loc.openTo(f.value)
+	case "tz":
+		lo, hi, ok := loc.timezoneWindow()
+		return ok && lo <= f.tzMax && hi >= f.tzMin
+	default:
+		return strings.Contains(strings.ToLower(loc.Raw), f.value)
+	}
+}
+
+func containsWord(haystack, word string) bool {
+	return wordIndex(haystack, word) >= 0
+}
+
+// wordIndex returns where word first appears in haystack as a whole word,
+// or -1.
+func wordIndex(haystack, word string) int {
+	idx := strings.Index(haystack, word)
+	for idx != -1 {
+		before := idx == 0 || !isWordChar(haystack[idx-1])
+		end := idx + len(word)
+		after := end >= len(haystack) || !isWordChar(haystack[end])
+		if before && after {
+			return idx
+		}
+		next := strings.Index(haystack[idx+1:], word)
+		if next == -1 {
+			break
+		}
+		idx += next + 1
+	}
+	return -1
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		city    string
+		region  string
+		country string
+		remote  bool
+		scope   []string
+	}{
+		{"us city and state", "Austin, TX", "Austin", "TX", "US", false, nil},
+		{"known city", "San Francisco Bay Area", "San Francisco", "CA", "US", false, nil},
+		{"canadian province", "Toronto, ON, Canada", "Toronto", "ON", "CA", false, nil},
+		{"remote us only", "Remote - US only", "", "", "US", true, []string{"US"}},
+		{"remote emea", "Remote (EMEA)", "", "", "", true, []string{"EMEA"}},
+		{"worldwide", "Anywhere in the world", "", "", "", true, []string{"WORLDWIDE"}},
+		{"european city", "Berlin, Germany", "Berlin", "", "DE", false, nil},
+		{"hybrid", "Hybrid - New York, NY", "New York", "NY", "US", false, nil},
+		{"state containing another state", "West Virginia", "", "WV", "US", false, nil},
+		{"state after a longer one", "Virginia or West Virginia", "", "VA", "US", false, nil},
+		{"first city mentioned", "New York or San Francisco", "New York", "NY", "US", false, nil},
+		{"first city mentioned reversed", "San Francisco or New York", "San Francisco", "CA", "US", false, nil},
+		{"longer city name at the same position", "Mexico City, Mexico", "Mexico City", "", "MX", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Run repeatedly: map iteration order used to pick the result.
+			for i := 0; i < 20; i++ {
+				if got := parseLocation(tt.text); got.City != tt.city || got.Region != tt.region || got.Country != tt.country {
+					t.Fatalf("run %d: parseLocation(%q) = %q %q %q", i, tt.text, got.City, got.Region, got.Country)
+				}
+			}
+			got := parseLocation(tt.text)
+			if got.City != tt.city || got.Region != tt.region || got.Country != tt.country || got.Remote != tt.remote {
+				t.Errorf("parseLocation(%q) = city %q region %q country %q remote %v, want %q %q %q %v",
+					tt.text, got.City, got.Region, got.Country, got.Remote, tt.city, tt.region, tt.country, tt.remote)
+			}
+			if !reflect.DeepEqual(got.RemoteScope, tt.scope) {
+				t.Errorf("parseLocation(%q).RemoteScope = %v, want %v", tt.text, got.RemoteScope, tt.scope)
+			}
+		})
+	}
+}
+
+func TestParseLocationTimezones(t *testing.T) {
+	tests := []struct {
+		text     string
+		min, max float64
+	}{
+		{"Remote (UTC-8 to UTC-5)", -8, -5},
+		{"Remote, GMT+1 ± 2 hours", -1, 3},
+		{"Remote, overlap with PST or EST", -8, -5},
+		{"Remote, UTC+5:30", 5.5, 5.5},
+	}
+
+	for _, tt := range tests {
+		got := parseLocation(tt.text)
+		if !got.HasTZ || got.TZMin != tt.min || got.TZMax != tt.max {
+			t.Errorf("parseLocation(%q) tz = %v..%v (%v), want %v..%v", tt.text, got.TZMin, got.TZMax, got.HasTZ, tt.min, tt.max)
+		}
+	}
+}
+
+func TestLocationFilters(t *testing.T) {
+	tests := []struct {
+		filter   string
+		location string
+		want     bool
+	}{
+		{"remote", "Remote", true},
+		{"remote", "Austin, TX", false},
+		{"remote:us", "Remote - US only", true},
+		{"remote:us", "Remote (EMEA)", false},
+		{"remote:us", "Remote", true},
+		{"remote:ca", "Remote - North America", true},
+		{"country:ca", "Vancouver, BC", true},
+		{"country:ca", "Seattle, WA", false},
+		{"us", "Tampa, FL", true},
+		{"usa", "London, UK", false},
+		{"region:emea", "Amsterdam, Netherlands", true},
+		{"tz:UTC-8..UTC-5", "Remote (UTC-6 to UTC-3)", true},
+		{"tz:UTC-8..UTC-5", "Remote, CET", false},
+		{"tz:UTC-8..UTC-5", "Denver, CO", true},
+		{"berlin", "Berlin, Germany", true},
+	}
+
+	for _, tt := range tests {
+		filters, err := parseLocationFilters(tt.filter)
+		if err != nil {
+			t.Fatalf("parseLocationFilters(%q): %v", tt.filter, err)
+		}
+		got := filterByLocation([]ScanResult{{Location: tt.location}}, filters)
+		if (len(got) == 1) != tt.want {
+			t.Errorf("filter %q on %q = %v, want %v", tt.filter, tt.location, len(got) == 1, tt.want)
+		}
+	}
+}
+
+func TestParseLocationFiltersRejectsUnknown(t *testing.T) {
+	for _, expr := range []string{"country:atlantis", "tz:soon", "planet:mars"} {
+		if _, err := parseLocationFilters(expr); err == nil {
+			t.Errorf("parseLocationFilters(%q) succeeded, want error", expr)
+		}
+	}
+}
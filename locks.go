@@ -0,0 +1,370 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Template annotations, written as LaTeX comments:
+//
+//	\item Flagship bullet % resumectl:lock        locks that line
+//	% resumectl:lock                               locks the next line, or the
+//	\begin{rSection}{Education}                    whole environment it opens
+//	% resumectl:lock-begin ... % resumectl:lock-end  locks everything between
+//	% resumectl:pin-top                            keeps the next bullet first
+const (
+	lockMark      = "resumectl:lock"
+	lockBeginMark = "resumectl:lock-begin"
+	lockEndMark   = "resumectl:lock-end"
+	pinTopMark    = "resumectl:pin-top"
+)
+
+type lockedRegion struct {
+	start, end int
+}
+
+// annotation returns the resumectl mark in line's comment, if any.
+func annotation(line string) string {
+	_, comment := splitComment(line)
+	for _, f := range strings.Fields(strings.TrimLeft(comment, "% ")) {
+		if strings.HasPrefix(f, "resumectl:") {
+			return f
+		}
+	}
+	return ""
+}
+
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "%")
+}
+
+// findLockedRegions returns the line ranges of lines that must survive
+// tailoring unchanged. Standalone annotation lines are part of their region
+// so the locks carry over to later iterations.
+func findLockedRegions(lines []string) []lockedRegion {
+	var regions []lockedRegion
+	for i := 0; i < len(lines); i++ {
+		mark := annotation(lines[i])
+		switch {
+		case mark == lockBeginMark:
+			end := i + 1
+			for end < len(lines) && annotation(lines[end]) != lockEndMark {
+				end++
+			}
+			if end < len(lines) {
+				end++
+			}
+			regions = append(regions, lockedRegion{i, end})
+			i = end - 1
+		case mark == lockMark && isCommentLine(lines[i]):
+			j := i + 1
+			for j < len(lines) && strings.TrimSpace(lines[j]) == "" {
+				j++
+			}
+			if j == len(lines) {
+				continue
+			}
+			end := j + 1
+			if env := envName(beginRe, lines[j]); env != "" {
+				depth := 1
+				for ; end < len(lines) && depth > 0; end++ {
+					if envName(beginRe, lines[end]) == env {
+						depth++
+					} else if envName(endRe, lines[end]) == env {
+						depth--
+					}
+				}
+			}
+			regions = append(regions, lockedRegion{i, end})
+			i = end - 1
+		case mark == lockMark:
+			regions = append(regions, lockedRegion{i, i + 1})
+		}
+	}
+	return regions
+}
+
+// enforceLocks restores locked regions and pinned bullets of the original
+// template in a tailored resume. It returns the repaired LaTeX and a
+// description of every violation it fixed.
+func enforceLocks(original, tailored string) (string, []string) {
+	if !strings.Contains(original, "resumectl:") {
+		return tailored, nil
+	}
+	origLines := strings.Split(original, "\n")
+	lines := strings.Split(tailored, "\n")
+
+	var violations []string
+	for _, r := range findLockedRegions(origLines) {
+		region := origLines[r.start:r.end]
+		if indexLines(lines, region) >= 0 {
+			continue
+		}
+		lines = restoreRegion(origLines, lines, r)
+		violations = append(violations, fmt.Sprintf("locked %s changed, restored: %s", lineRange(r), regionLabel(region)))
+	}
+
+	result := strings.Join(lines, "\n")
+	result, pinned, err := enforcePins(original, result)
+	if err != nil {
+		violations = append(violations, fmt.Sprintf("could not check pinned bullets: %v", err))
+	}
+	return result, append(violations, pinned...)
+}
+
+func lineRange(r lockedRegion) string {
+	if r.end-r.start == 1 {
+		return fmt.Sprintf("line %d", r.start+1)
+	}
+	return fmt.Sprintf("lines %d-%d", r.start+1, r.end)
+}
+
+func regionLabel(region []string) string {
+	for _, l := range region {
+		if t := strings.TrimSpace(l); t != "" && !isCommentLine(t) {
+			text, _ := splitComment(t)
+			return truncate(strings.TrimSpace(text), 60)
+		}
+	}
+	return "(comments only)"
+}
+
+// indexLines finds region as a contiguous run in lines, ignoring trailing
+// whitespace.
+func indexLines(lines, region []string) int {
+	for i := 0; i+len(region) <= len(lines); i++ {
+		match := true
+		for j, l := range region {
+			if strings.TrimRight(lines[i+j], " \t\r") != strings.TrimRight(l, " \t\r") {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// restoreRegion puts an original locked region back into tailored lines.
+// Lines common to both versions anchor the region's position; tailored
+// lines between the anchors that are edits of locked lines are replaced.
+func restoreRegion(orig, lines []string, r lockedRegion) []string {
+	match := alignLines(orig, lines)
+
+	before, after := -1, len(lines)
+	origGapStart := 0
+	for i := r.start - 1; i >= 0; i-- {
+		if match[i] >= 0 {
+			before, origGapStart = match[i], i+1
+			break
+		}
+	}
+	for i := r.end; i < len(orig); i++ {
+		if match[i] >= 0 {
+			after = match[i]
+			break
+		}
+	}
+
+	region := orig[r.start:r.end]
+	gap := lines[before+1 : after]
+	var kept []string
+	insertAt := -1
+	for _, l := range gap {
+		if replacesLocked(l, region) {
+			if insertAt < 0 {
+				insertAt = len(kept)
+			}
+			continue
+		}
+		kept = append(kept, l)
+	}
+	if insertAt < 0 {
+		insertAt = r.start - origGapStart
+		if insertAt > len(kept) {
+			insertAt = len(kept)
+		}
+	}
+
+	out := append([]string(nil), lines[:before+1]...)
+	out = append(out, kept[:insertAt]...)
+	out = append(out, region...)
+	out = append(out, kept[insertAt:]...)
+	return append(out, lines[after:]...)
+}
+
+// replacesLocked reports whether a tailored line looks like an edit of one
+// of the locked lines.
+func replacesLocked(line string, region []string) bool {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" {
+		return false
+	}
+	words := contentWords(latexPlain(line))
+	for _, l := range region {
+		if strings.TrimSpace(l) == trimmed {
+			return true
+		}
+		if env := envName(beginRe, l); env != "" && env == envName(beginRe, line) {
+			return true
+		}
+		if env := envName(endRe, l); env != "" && env == envName(endRe, line) {
+			return true
+		}
+		if len(words) > 0 && wordSimilarity(words, contentWords(latexPlain(l))) >= driftSimilarityThreshold {
+			return true
+		}
+	}
+	return false
+}
+
+// alignLines maps each line of a to its partner in b on a longest common
+// subsequence, or -1.
+func alignLines(a, b []string) []int {
+	norm := func(s string) string { return strings.TrimRight(s, " \t\r") }
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if norm(a[i]) == norm(b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case norm(a[i]) == norm(b[j]):
+			match[i] = j
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+func isPinned(b *Bullet) bool {
+	for _, l := range b.lead {
+		if annotation(l) == pinTopMark {
+			return true
+		}
+	}
+	return false
+}
+
+// enforcePins moves every bullet annotated with pin-top to the top of its
+// list in the tailored resume, restoring it from the original if the
+// tailoring dropped it.
+func enforcePins(original, tailored string) (string, []string, error) {
+	if !strings.Contains(original, pinTopMark) {
+		return tailored, nil, nil
+	}
+	origDoc, err := parseResume(original)
+	if err != nil {
+		return tailored, nil, err
+	}
+	doc, err := parseResume(tailored)
+	if err != nil {
+		return tailored, nil, err
+	}
+
+	lists := make(map[string]*ResumeList)
+	for _, l := range doc.Lists() {
+		if len(l.Bullets) > 0 {
+			lists[listKey(l.Bullets[0].ID)] = l
+		}
+	}
+
+	var violations []string
+	changed := false
+	for _, ol := range origDoc.Lists() {
+		var pins []*Bullet
+		for _, b := range ol.Bullets {
+			if isPinned(b) {
+				pins = append(pins, b)
+			}
+		}
+		if len(pins) == 0 {
+			continue
+		}
+		l := lists[listKey(ol.Bullets[0].ID)]
+		if l == nil {
+			violations = append(violations, fmt.Sprintf("pinned bullet %s: list missing from tailored resume", pins[0].ID))
+			continue
+		}
+
+		var top []*Bullet
+		used := make(map[*Bullet]bool)
+		for _, pin := range pins {
+			b := closestBullet(l.Bullets, pin, used)
+			if b == nil {
+				violations = append(violations, fmt.Sprintf("pinned bullet %s was dropped, restored: %s", pin.ID, truncate(pin.Text, 60)))
+				b = pin
+			} else if !isPinned(b) {
+				b.lead = pin.lead
+				changed = true
+			}
+			used[b] = true
+			top = append(top, b)
+		}
+
+		rest := make([]*Bullet, 0, len(l.Bullets))
+		for _, b := range l.Bullets {
+			if !used[b] {
+				rest = append(rest, b)
+			}
+		}
+		for i, b := range top {
+			if i < len(l.Bullets) && l.Bullets[i] == b {
+				continue
+			}
+			changed = true
+			if b != pins[i] {
+				violations = append(violations, fmt.Sprintf("pinned bullet %s moved back to the top: %s", pins[i].ID, truncate(pins[i].Text, 60)))
+			}
+		}
+		reordered := append(top, rest...)
+		l.Bullets = reordered
+	}
+
+	if !changed {
+		return tailored, violations, nil
+	}
+	return doc.Render(), violations, nil
+}
+
+// closestBullet finds the bullet in list that is the pinned bullet, or an
+// edit of it.
+func closestBullet(list []*Bullet, pin *Bullet, used map[*Bullet]bool) *Bullet {
+	var best *Bullet
+	bestScore := driftSimilarityThreshold
+	want := contentWords(latexPlain(pin.Text))
+	for _, b := range list {
+		if used[b] {
+			continue
+		}
+		if b.Text == pin.Text {
+			return b
+		}
+		if s := wordSimilarity(want, contentWords(latexPlain(b.Text))); s >= bestScore {
+			best, bestScore = b, s
+		}
+	}
+	return best
+}
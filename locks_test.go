@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+var lockedResume = strings.NewReplacer(
+	`{Staff Data Engineer}{Remote}`, `{Staff Data Engineer}{Remote} % resumectl:lock`,
+	"\\begin{rSection}{Education}", "% resumectl:lock\n\\begin{rSection}{Education}",
+	"\\item Designed the warehouse", "% resumectl:lock-begin\n\\item Designed the warehouse",
+	"\\item Cut nightly batch runtime by 60\\%\n", "\\item Cut nightly batch runtime by 60\\%\n% resumectl:lock-end\n",
+).Replace(strings.Replace(sampleResume, "  % resumectl:pin-top\n", "", 1))
+
+func TestFindLockedRegions(t *testing.T) {
+	lines := strings.Split(lockedResume, "\n")
+	var got []string
+	for _, r := range findLockedRegions(lines) {
+		got = append(got, regionLabel(lines[r.start:r.end]))
+	}
+	want := []string{
+		`\begin{rSubsection}{Acme Corp}{Jan 2021 -- Present}{Staff...`,
+		`\item Designed the warehouse schema in Snowflake`,
+		`\begin{rSection}{Education}`,
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestEnforceLocks(t *testing.T) {
+	tests := []struct {
+		name       string
+		edit       func(string) string
+		violations int
+		keep       string
+	}{
+		{"untouched", func(s string) string { return s }, 0, ""},
+		{"unlocked edit survives",
+			func(s string) string {
+				return strings.Replace(s, "Mentored 4 engineers", "Mentored 4 data engineers", 1)
+			},
+			0, "Mentored 4 data engineers"},
+		{"locked line rewritten",
+			func(s string) string {
+				s = strings.Replace(s, "{Staff Data Engineer}{Remote} % resumectl:lock", "{Principal Engineer}{Remote}", 1)
+				return strings.Replace(s, "Mentored 4 engineers", "Mentored 4 data engineers", 1)
+			},
+			1, "Mentored 4 data engineers"},
+		{"locked environment edited",
+			func(s string) string { return strings.Replace(s, "B.S. Computer Science", "M.S. Computer Science", 1) },
+			1, ""},
+		{"locked environment dropped",
+			func(s string) string {
+				start := strings.Index(s, "% resumectl:lock\n\\begin{rSection}{Education}")
+				end := strings.Index(s, "\\end{document}")
+				return s[:start] + s[end:]
+			},
+			1, ""},
+		{"lock-begin range reordered",
+			func(s string) string {
+				return strings.Replace(s, "\\item Designed the warehouse schema in Snowflake\n\\item Cut nightly batch runtime by 60\\%",
+					"\\item Cut nightly batch runtime by 60\\%\n\\item Designed the warehouse schema in Snowflake", 1)
+			},
+			1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, violations := enforceLocks(lockedResume, tt.edit(lockedResume))
+			if len(violations) != tt.violations {
+				t.Errorf("violations = %q, want %d", violations, tt.violations)
+			}
+			for _, r := range findLockedRegions(strings.Split(lockedResume, "\n")) {
+				region := strings.Split(lockedResume, "\n")[r.start:r.end]
+				if indexLines(strings.Split(got, "\n"), region) < 0 {
+					t.Errorf("locked region %s missing:\n%s", lineRange(r), got)
+				}
+			}
+			if tt.keep != "" && !strings.Contains(got, tt.keep) {
+				t.Errorf("lost unlocked edit %q:\n%s", tt.keep, got)
+			}
+			if again, v := enforceLocks(lockedResume, got); again != got || len(v) > 0 {
+				t.Errorf("second pass changed the result: %q", v)
+			}
+		})
+	}
+}
+
+func TestEnforcePins(t *testing.T) {
+	pinnedFirst := strings.Replace(sampleResume,
+		"  \\item Built a streaming platform on Kafka and Flink processing 2B events/day\n  \\item Led migration from Airflow 1 to Airflow 2\n    across 300 DAGs % multi-line bullet\n  % resumectl:pin-top\n  \\item Mentored 4 engineers\n",
+		"  % resumectl:pin-top\n  \\item Mentored 4 engineers\n  \\item Built a streaming platform on Kafka and Flink processing 2B events/day\n  \\item Led migration from Airflow 1 to Airflow 2\n    across 300 DAGs % multi-line bullet\n", 1)
+
+	tests := []struct {
+		name       string
+		tailored   string
+		violations []string
+	}{
+		{"kept first", pinnedFirst, nil},
+		{"moved down", sampleResume, []string{"pinned bullet R1.1 moved back to the top"}},
+		{"rewritten and moved down",
+			strings.Replace(sampleResume, "  % resumectl:pin-top\n  \\item Mentored 4 engineers", "  \\item Mentored 4 senior engineers", 1),
+			[]string{"pinned bullet R1.1 moved back to the top"}},
+		{"dropped",
+			strings.Replace(pinnedFirst, "  % resumectl:pin-top\n  \\item Mentored 4 engineers\n", "", 1),
+			[]string{"pinned bullet R1.1 was dropped"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, violations := enforceLocks(pinnedFirst, tt.tailored)
+			if len(violations) != len(tt.violations) {
+				t.Fatalf("violations = %q, want %q", violations, tt.violations)
+			}
+			for i, v := range violations {
+				if !strings.HasPrefix(v, tt.violations[i]) {
+					t.Errorf("violation %q, want prefix %q", v, tt.violations[i])
+				}
+			}
+			doc, err := parseResume(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			first := doc.Roles()[0].Bullets[0]
+			if !strings.HasPrefix(first.Text, "Mentored 4") || !isPinned(first) {
+				t.Errorf("first bullet = %q, pinned %v", first.Text, isPinned(first))
+			}
+		})
+	}
+}
@@ -27,6 +27,8 @@ var (
 	targetScore     int
 	modelName       string
 	withCoverLetter bool
+	matchMode       string
+	matchStrict     bool
 )
 
 type JobInfo struct {
@@ -34,6 +36,7 @@ type JobInfo struct {
 	Title       string
 	ReqID       string
 	Description string
+	Pay         SalaryRange
 }
 
 type MatchResult struct {
@@ -41,6 +44,14 @@ type MatchResult struct {
 	StrongMatches []string `json:"strong_matches"`
 	Gaps          []string `json:"gaps"`
 	TailoredLatex string   `json:"tailored_latex"`
+
+	Drift          []DriftFinding   `json:"-"`
+	LockViolations []string         `json:"-"`
+	Trimmed        []string         `json:"-"`
+	LatexFixes     []string         `json:"-"`
+	Review         []reviewDecision `json:"-"`
+	Ranking        []templateRank   `json:"-"`
+	Bank           string           `json:"-"` // hash of the experience bank the resume was assembled from
 }
 
 func main() {
@@ -50,8 +61,8 @@ func main() {
 	}
 
 	var matchCmd = &cobra.Command{
-		Use:   "match [job-url]",
-		Short: "Match resume against a job posting URL or file",
+		Use:   "match [job-url|job-id]",
+		Short: "Match resume against a job posting URL, stored job ID, or file",
 		Args:  cobra.MaximumNArgs(1),
 		Run:   runMatch,
 	}
@@ -62,6 +73,12 @@ func main() {
 	matchCmd.Flags().IntVarP(&targetScore, "target", "t", 85, "Target score to stop iterating")
 	matchCmd.Flags().StringVarP(&modelName, "model", "m", "claude-sonnet-4-20250514", "Anthropic model to use")
 	matchCmd.Flags().BoolVar(&withCoverLetter, "cover-letter", false, "Also generate a cover letter")
+	matchCmd.Flags().StringVar(&matchMode, "mode", "rewrite", "Tailoring mode: rewrite (model edits the LaTeX) or reorder (model only reorders and drops existing bullets and skills)")
+	matchCmd.Flags().IntVar(&maxPages, "max-pages", 0, "Compile and drop the lowest-priority bullets and skills until the resume fits this many pages")
+	matchCmd.Flags().BoolVar(&matchStrict, "strict", false, "Fail the run when the tailored resume drifts from the original (new numbers, terms, roles or rewritten bullets)")
+	matchCmd.Flags().IntVar(&bankBullets, "bank-bullets", defaultBankBullets, "Bullets to keep when assembling from experience.bank.tex")
+	matchCmd.Flags().BoolVar(&matchReview, "review", false, "Accept, reject or edit each change tailoring made before saving")
+	matchCmd.Flags().StringSliceVar(&outputFormats, "formats", nil, "Also write the tailored resume as txt, md, html and/or docx (comma-separated)")
 	rootCmd.AddCommand(matchCmd)
 
 	var listCmd = &cobra.Command{
@@ -69,19 +86,40 @@ func main() {
 		Short: "List saved jobs from database",
 		Run:   runList,
 	}
-	listCmd.Flags().String("status", "", "Filter by status (new, applied, screening, interview, offer, rejected)")
+	listCmd.Flags().String("status", "", "Filter by status (discovered, new, applied, screening, interview, offer, rejected)")
 	listCmd.Flags().Int("min-score", 0, "Filter by minimum score")
+	listCmd.Flags().String("sort", "", "Sort by: salary, score (default: newest first)")
 	rootCmd.AddCommand(listCmd)
 
 	var pdfCmd = &cobra.Command{
 		Use:   "pdf [results-dir]",
-		Short: "Compile resume.tex to PDF using tectonic",
+		Short: "Compile resume.tex to PDF (tectonic, or RESUMECTL_LATEX_ENGINE)",
 		Args:  cobra.ExactArgs(1),
 		Run:   runPdf,
 	}
 	rootCmd.AddCommand(pdfCmd)
 
+	var atsCheckCmd = &cobra.Command{
+		Use:   "ats-check [results-dir]",
+		Short: "Check that contact details, headings, employers, dates and matched keywords extract from resume.pdf",
+		Args:  cobra.ExactArgs(1),
+		Run:   runATSCheck,
+	}
+	rootCmd.AddCommand(atsCheckCmd)
+
+	var diffCmd = &cobra.Command{
+		Use:   "diff [results-dir|job]",
+		Short: "Show bullets moved, removed and edited between the source template and a tailored resume",
+		Args:  cobra.ExactArgs(1),
+		Run:   runDiff,
+	}
+	diffCmd.Flags().StringVar(&diffBase, "base", "", "Template to compare against (default: the one recorded for the match run)")
+	diffCmd.Flags().BoolVar(&diffHTML, "html", false, "Also write a shareable diff.html to the results directory")
+	rootCmd.AddCommand(diffCmd)
+
 	scanCmd.Flags().StringVarP(&resumePath, "resume", "r", "resume.template.data-platform.tex", "Path to resume LaTeX file")
+	scanCmd.Flags().IntVar(&maxPages, "max-pages", 0, "Trim matched resumes to this many pages (with --auto-match or --interactive)")
+	scanCmd.Flags().IntVar(&bankBullets, "bank-bullets", defaultBankBullets, "Bullets to keep when assembling from experience.bank.tex (with --auto-match or --interactive)")
 	rootCmd.AddCommand(scanCmd)
 
 	var prepCmd = &cobra.Command{
@@ -121,7 +159,7 @@ func main() {
 
 	var statusCmd = &cobra.Command{
 		Use:   "status <company|url|id> <status>",
-		Short: "Update a job's status (applied, screening, interview, offer, rejected, withdrawn)",
+		Short: "Update a job's status (discovered, new, applied, screening, interview, offer, rejected, withdrawn)",
 		Args:  cobra.ExactArgs(2),
 		Run: func(cmd *cobra.Command, args []string) {
 			if err := InitDB(); err != nil {
@@ -150,6 +188,8 @@ func main() {
 	}
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(syncCmd)
+	rootCmd.AddCommand(resumeCmd)
+	rootCmd.AddCommand(templateCmd)
 
 	var gmailAuthCmd = &cobra.Command{
 		Use:   "gmail-auth",
@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,71 +11,60 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
-func selectBestTemplate(job *JobInfo) string {
-	templates, _ := filepath.Glob("resume.template*.tex")
-	if len(templates) <= 1 {
-		return resumePath
-	}
-
-	fmt.Printf("\n%s Found %d resume templates, selecting best match...\n", color.CyanString("→"), len(templates))
-
-	var results []scoredTemplate
-
-	for _, t := range templates {
-		resume, err := os.ReadFile(t)
-		if err != nil {
-			continue
-		}
-		label := templateLabel(t)
-		score, err := scoreTemplate(string(resume), job.Title, job.Description, label)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "  Warning: could not score %s: %v\n", t, err)
-			continue
+// selectBestTemplate returns the resume to tailor for job and, when there
+// were several templates to choose from, their ranking. When the resume was
+// assembled from the experience bank it also returns the bank's hash.
+func selectBestTemplate(job *JobInfo, w io.Writer) (path string, ranking []templateRank, bankHash string) {
+	if bank := findExperienceBank(); bank != "" {
+		assembled, hash, err := assembleFromBank(bank, job, w)
+		if err == nil {
+			return assembled, nil, hash
 		}
-		fmt.Printf("  %s: %s\n", filepath.Base(t), color.CyanString("%d/100", score))
-		results = append(results, scoredTemplate{t, label, score})
-	}
-
-	if len(results) == 0 {
-		return resumePath
+		fmt.Fprintf(w, "  %s Could not assemble from %s, falling back to templates: %v\n", color.YellowString("⚠"), bank, err)
 	}
 
-	best := results[0]
-
-	tied := false
-	for _, r := range results[1:] {
-		if r.score > best.score {
-			best = r
-			tied = false
-		} else if r.score == best.score {
-			tied = true
-		}
+	templates, _ := loadTemplates()
+	switch len(templates) {
+	case 0:
+		return resumePath, nil, ""
+	case 1:
+		return templates[0].Path, nil, ""
 	}
 
-	if tied {
-		fmt.Printf("  %s Tie detected, comparing directly...\n", color.YellowString("⚠"))
-		winner, err := compareTemplates(results, job.Title, job.Description)
-		if err == nil {
-			best = winner
-		}
+	fmt.Fprintf(w, "\n%s Found %d resume templates, ranking them...\n", color.CyanString("→"), len(templates))
+	ranking, err := rankTemplates(templates, job)
+	if err != nil {
+		fmt.Fprintf(w, "  %s Could not rank templates, using %s: %v\n", color.YellowString("⚠"), templates[0].Label, err)
+		return templates[0].Path, nil, ""
 	}
-
-	fmt.Printf("  %s Selected: %s\n", color.GreenString("✓"), filepath.Base(best.path))
-	return best.path
+	printRanking(w, ranking)
+	fmt.Fprintf(w, "  %s Selected: %s\n", color.GreenString("✓"), ranking[0].Label)
+	return ranking[0].Path, ranking, ""
 }
 
 func runMatch(cmd *cobra.Command, args []string) {
+	if matchMode != "rewrite" && matchMode != "reorder" {
+		fmt.Fprintf(os.Stderr, "Unknown mode: %s\nAvailable: rewrite, reorder\n", matchMode)
+		os.Exit(1)
+	}
+	if err := checkFormats(outputFormats); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	if err := InitDB(); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: could not init database: %v\n", err)
 	}
 
 	var job *JobInfo
+	var jobURL string
 	var err error
 
 	if jobFile != "" {
@@ -114,12 +104,35 @@ func runMatch(cmd *cobra.Command, args []string) {
 			Description: string(content),
 		}
 	} else if len(args) > 0 {
+		jobURL = args[0]
+		var stored *Job
+		if id, convErr := strconv.Atoi(args[0]); convErr == nil {
+			if db == nil {
+				fmt.Fprintf(os.Stderr, "Error: matching by job ID requires the database (NEON_DB_URL)\n")
+				os.Exit(1)
+			}
+			stored, err = FindJobByID(id)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if strings.HasPrefix(stored.URL, "file://") {
+				fmt.Fprintf(os.Stderr, "Error: job %d was matched from a file — use: resumectl match --file %s\n", id, strings.TrimPrefix(stored.URL, "file://"))
+				os.Exit(1)
+			}
+			fmt.Printf("Matched: [%d] %s — %s\n", stored.ID, stored.Company, stored.Title)
+			jobURL = stored.URL
+		}
+
 		fmt.Println("Fetching job description...")
-		job, err = fetchJobDescription(args[0])
+		job, err = fetchJobDescription(jobURL)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error fetching job: %v\n", err)
 			os.Exit(1)
 		}
+		if stored != nil && job.Company == "unknown" {
+			job.Company = stored.Company
+		}
 		if companyName != "" {
 			job.Company = companyName
 		}
@@ -129,6 +142,9 @@ func runMatch(cmd *cobra.Command, args []string) {
 	}
 
 	fmt.Printf("Job: %s at %s\n", job.Title, job.Company)
+	if !job.Pay.IsZero() {
+		fmt.Printf("Salary: %s\n", job.Pay)
+	}
 
 	descLen := len(strings.TrimSpace(job.Description))
 	if descLen < 200 {
@@ -137,8 +153,13 @@ func runMatch(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	var ranking []templateRank
+	var bankHash string
 	if !cmd.Flags().Changed("resume") {
-		resumePath = selectBestTemplate(job)
+		if err := recordTemplateEdits(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record template edits: %v\n", err)
+		}
+		resumePath, ranking, bankHash = selectBestTemplate(job, os.Stdout)
 	}
 
 	resume, err := os.ReadFile(resumePath)
@@ -147,103 +168,62 @@ func runMatch(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	currentResume := string(resume)
-	var bestResult *MatchResult
-	var bestScore int
-
-	for iteration := 1; iteration <= maxIterations; iteration++ {
-		fmt.Printf("\n%s Iteration %d/%d\n", color.CyanString("→"), iteration, maxIterations)
-		fmt.Println("Analyzing and tailoring...")
-
-		result, err := analyzeAndTailor(currentResume, job.Description)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error analyzing: %v\n", err)
-			os.Exit(1)
-		}
-
-		printIterationResult(iteration, result)
-
-		if result.Score > bestScore {
-			bestScore = result.Score
-			bestResult = result
-		}
-
-		if result.Score >= targetScore {
-			fmt.Printf("\n%s Target score %d reached!\n", color.GreenString("✓"), targetScore)
-			break
-		}
-
-		if iteration > 1 && result.Score <= bestScore-5 {
-			fmt.Printf("\n%s Score not improving, stopping.\n", color.YellowString("⚠"))
-			break
-		}
-
-		currentResume = result.TailoredLatex
+	bestResult, err := tailorIterations(string(resume), job, os.Stdout)
+	if bestResult != nil {
+		bestResult.Ranking, bestResult.Bank = ranking, bankHash
 	}
 
 	fmt.Println()
 	fmt.Println(color.New(color.Bold, color.Underline).Sprint("Final Results"))
 	fmt.Println(strings.Repeat("═", 50))
-	if bestResult == nil {
-		fmt.Fprintf(os.Stderr, "Error: no valid results produced. The job description may be empty or the API returned invalid responses.\n")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 	printResult(bestResult)
 
-	if fabricated := detectFabrication(string(resume), bestResult.TailoredLatex); len(fabricated) > 0 {
-		fmt.Printf("\n%s Potential fabrication detected — these terms appear in the tailored resume but NOT in your original:\n", color.RedString("⚠ WARNING"))
-		for _, t := range fabricated {
-			fmt.Printf("  %s %s\n", color.RedString("✗"), t)
-		}
-		fmt.Println("Review the tailored resume carefully before using it.")
-	}
+	printLockViolations(os.Stdout, bestResult.LockViolations)
+	printDrift(os.Stdout, bestResult.Drift)
 
-	if db != nil {
-		var jobURL string
-		if len(args) > 0 {
-			jobURL = args[0]
-		} else if jobFile != "" {
-			jobURL = "file://" + jobFile
-		}
-		if jobURL != "" {
-			if err := SaveJob(jobURL, job.Company, job.Title, bestResult.Score); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: could not save to database: %v\n", err)
-			} else {
-				fmt.Printf("%s Saved to database\n", color.GreenString("✓"))
-
-				outputDir := generateOutputDir(job)
-				if err := SaveMatchRun(jobURL, bestResult.Score, bestResult.StrongMatches, bestResult.Gaps, contentHash(string(resume)), contentHash(bestResult.TailoredLatex), outputDir); err != nil {
-					fmt.Fprintf(os.Stderr, "Warning: could not save match run: %v\n", err)
-				}
-			}
+	// One reader serves every prompt, so input typed ahead isn't lost in a
+	// discarded buffer.
+	stdin := bufio.NewReader(os.Stdin)
+	if matchReview {
+		if err := reviewMatchResult(string(resume), bestResult, stdin, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: review: %v\n", err)
+			os.Exit(1)
 		}
 	}
 
 	outputDir := generateOutputDir(job)
-	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		fmt.Fprintf(os.Stderr, "Error creating output dir: %v\n", err)
+	fitMatchResult(outputDir, string(resume), bestResult, maxPages, os.Stdout)
+
+	if err := writeMatchOutputs(outputDir, job, bestResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving results: %v\n", err)
 		os.Exit(1)
 	}
+	formats, err := writeResumeFormats(outputDir, bestResult.TailoredLatex, outputFormats)
+	if err != nil {
+		fmt.Printf("%s Other formats: %v\n", color.YellowString("⚠"), err)
+	}
 
-	resumeOut := filepath.Join(outputDir, "resume.tex")
-	if err := os.WriteFile(resumeOut, []byte(bestResult.TailoredLatex), 0644); err != nil {
-		fmt.Fprintf(os.Stderr, "Error saving tailored resume: %v\n", err)
+	if matchStrict && len(bestResult.Drift) > 0 {
+		fmt.Fprintf(os.Stderr, "\n--strict: %d drift findings, not saving. Review %s/report.txt\n", len(bestResult.Drift), outputDir)
 		os.Exit(1)
 	}
 
-	jobOut := filepath.Join(outputDir, "job.txt")
-	os.WriteFile(jobOut, []byte(job.Description), 0644)
-
-	reportOut := filepath.Join(outputDir, "report.txt")
-	report := fmt.Sprintf("Score: %d/100\n\nStrong Matches:\n", bestResult.Score)
-	for _, m := range bestResult.StrongMatches {
-		report += fmt.Sprintf("  - %s\n", m)
-	}
-	report += "\nGaps:\n"
-	for _, g := range bestResult.Gaps {
-		report += fmt.Sprintf("  - %s\n", g)
+	if db != nil {
+		if jobFile != "" {
+			jobURL = "file://" + jobFile
+		}
+		if jobURL != "" {
+			if err := recordMatch(jobURL, job, bestResult, resumePath, string(resume), outputDir); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			} else {
+				fmt.Printf("%s Saved to database\n", color.GreenString("✓"))
+			}
+		}
 	}
-	os.WriteFile(reportOut, []byte(report), 0644)
 
 	if withCoverLetter {
 		fmt.Println()
@@ -260,8 +240,7 @@ func runMatch(cmd *cobra.Command, args []string) {
 		fmt.Println("(e.g. side projects, motivation, relocation plans)")
 		fmt.Printf("Press Enter to skip: ")
 
-		reader := bufio.NewReader(os.Stdin)
-		line, _ := reader.ReadString('\n')
+		line, _ := stdin.ReadString('\n')
 		userContext := strings.TrimSpace(line)
 
 		fmt.Println("Generating cover letter...")
@@ -279,6 +258,12 @@ func runMatch(cmd *cobra.Command, args []string) {
 	fmt.Printf("  resume.tex  - tailored resume\n")
 	fmt.Printf("  job.txt     - job description\n")
 	fmt.Printf("  report.txt  - match report\n")
+	if _, err := os.Stat(filepath.Join(outputDir, "resume.pdf")); err == nil {
+		fmt.Printf("  resume.pdf  - compiled resume\n")
+	}
+	for _, f := range formats {
+		fmt.Printf("  %-11s - tailored resume (%s)\n", f, strings.TrimPrefix(filepath.Ext(f), "."))
+	}
 	if withCoverLetter {
 		fmt.Printf("  cover-letter.txt - cover letter\n")
 	}
@@ -287,6 +272,266 @@ func runMatch(cmd *cobra.Command, args []string) {
 	fmt.Printf("  resumectl pdf %s\n", outputDir)
 }
 
+// tailorIterations runs tailorResume until the target score is reached or
+// the score stops improving, returning the best result.
+func tailorIterations(resume string, job *JobInfo, w io.Writer) (*MatchResult, error) {
+	currentResume := resume
+	var bestResult *MatchResult
+	var bestScore int
+
+	for iteration := 1; iteration <= maxIterations; iteration++ {
+		fmt.Fprintf(w, "\n%s Iteration %d/%d\n", color.CyanString("→"), iteration, maxIterations)
+		fmt.Fprintln(w, "Analyzing and tailoring...")
+
+		result, err := tailorResume(currentResume, job.Description)
+		if err != nil {
+			return nil, fmt.Errorf("analyzing: %v", err)
+		}
+		result.TailoredLatex, result.LockViolations = enforceLocks(resume, result.TailoredLatex)
+		if n := len(result.LockViolations); n > 0 {
+			fmt.Fprintf(w, "  %s Restored %d locked or pinned regions\n", color.YellowString("⚠"), n)
+		}
+
+		printIterationResult(w, iteration, result)
+
+		if result.Score > bestScore {
+			bestScore = result.Score
+			bestResult = result
+		}
+
+		if result.Score >= targetScore {
+			fmt.Fprintf(w, "\n%s Target score %d reached!\n", color.GreenString("✓"), targetScore)
+			break
+		}
+
+		if iteration > 1 && result.Score <= bestScore-5 {
+			fmt.Fprintf(w, "\n%s Score not improving, stopping.\n", color.YellowString("⚠"))
+			break
+		}
+
+		currentResume = result.TailoredLatex
+	}
+
+	if bestResult == nil {
+		return nil, fmt.Errorf("no valid results produced. The job description may be empty or the API returned invalid responses")
+	}
+	bestResult.Drift = detectDrift(resume, bestResult.TailoredLatex)
+	return bestResult, nil
+}
+
+func recordMatch(jobURL string, job *JobInfo, result *MatchResult, sourcePath, sourceResume, outputDir string) error {
+	if err := SaveJob(jobURL, job.Company, job.Title, job.Description, result.Score); err != nil {
+		return fmt.Errorf("could not save to database: %v", err)
+	}
+	if err := SaveJobSalary(jobURL, job.Pay); err != nil {
+		return fmt.Errorf("could not save salary: %v", err)
+	}
+	err := SaveMatchRun(matchRun{
+		JobURL:        jobURL,
+		Score:         result.Score,
+		StrongMatches: result.StrongMatches,
+		Gaps:          result.Gaps,
+		SourceHash:    contentHash(sourceResume),
+		BankHash:      result.Bank,
+		TailoredHash:  contentHash(result.TailoredLatex),
+		OutputDir:     outputDir,
+		Review:        result.Review,
+		Template:      sourcePath,
+		Ranking:       result.Ranking,
+	})
+	if err != nil {
+		return fmt.Errorf("could not save match run: %v", err)
+	}
+	return nil
+}
+
+func writeMatchOutputs(outputDir string, job *JobInfo, result *MatchResult) error {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "resume.tex"), []byte(result.TailoredLatex), 0644); err != nil {
+		return err
+	}
+	os.WriteFile(filepath.Join(outputDir, "job.txt"), []byte(job.Description), 0644)
+	return os.WriteFile(filepath.Join(outputDir, "report.txt"), []byte(matchReport(result)), 0644)
+}
+
+// compileMatchOutputs compiles outputDir/resume.tex, repairing LaTeX
+// errors, and rewrites the outputs when a repair changed the source.
+func compileMatchOutputs(outputDir, original string, job *JobInfo, result *MatchResult) error {
+	fixes, err := compileWithRepair(outputDir, "resume.tex")
+	if len(fixes) == 0 {
+		return err
+	}
+	compiled, readErr := adoptLatexRepairs(outputDir, original, result, fixes)
+	if readErr != nil {
+		return readErr
+	}
+	if writeErr := writeMatchOutputs(outputDir, job, result); writeErr != nil {
+		return writeErr
+	}
+	if err == nil && compiled != result.TailoredLatex {
+		if _, err = compileResume(outputDir, "resume.tex"); err != nil {
+			err = fmt.Errorf("compiling with locked regions restored: %v", err)
+		}
+	}
+	return err
+}
+
+// adoptLatexRepairs takes the repaired outputDir/resume.tex into result. A
+// model repair can rewrite more than the broken lines, so locked regions are
+// restored and drift is checked again against original. It returns the
+// repaired source as compiled, before locks were restored.
+func adoptLatexRepairs(outputDir, original string, result *MatchResult, fixes []string) (string, error) {
+	src, err := os.ReadFile(filepath.Join(outputDir, "resume.tex"))
+	if err != nil {
+		return "", err
+	}
+	result.LatexFixes = append(result.LatexFixes, fixes...)
+	latex, restored := enforceLocks(original, string(src))
+	result.TailoredLatex = latex
+	result.LockViolations = append(result.LockViolations, restored...)
+	result.Drift = detectDrift(original, latex)
+	return string(src), nil
+}
+
+func matchReport(result *MatchResult) string {
+	report := fmt.Sprintf("Score: %d/100\n\nStrong Matches:\n", result.Score)
+	for _, m := range result.StrongMatches {
+		report += fmt.Sprintf("  - %s\n", m)
+	}
+	report += "\nGaps:\n"
+	for _, g := range result.Gaps {
+		report += fmt.Sprintf("  - %s\n", g)
+	}
+	if len(result.LockViolations) > 0 {
+		report += "\nLocked content restored:\n"
+		for _, v := range result.LockViolations {
+			report += fmt.Sprintf("  - %s\n", v)
+		}
+	}
+	if len(result.Trimmed) > 0 {
+		report += "\nRemoved to fit the page limit:\n"
+		for _, t := range result.Trimmed {
+			report += fmt.Sprintf("  - %s\n", t)
+		}
+	}
+	if len(result.LatexFixes) > 0 {
+		report += "\nLaTeX fixes:\n"
+		for _, f := range result.LatexFixes {
+			report += fmt.Sprintf("  - %s\n", f)
+		}
+	}
+	if len(result.Review) > 0 {
+		report += "\n" + reviewSummary(result.Review) + ":\n"
+		for _, d := range result.Review {
+			switch d.Decision {
+			case "reject":
+				report += fmt.Sprintf("  - rejected %s (%s): %s\n", d.Kind, d.Where, cmp.Or(d.New, d.Old))
+			case "edit":
+				report += fmt.Sprintf("  - edited %s (%s): %s → %s\n", d.Kind, d.Where, cmp.Or(d.New, d.Old), d.Edited)
+			}
+		}
+	}
+	if len(result.Ranking) > 0 {
+		report += "\nTemplate ranking:\n"
+		for i, r := range result.Ranking {
+			report += fmt.Sprintf("  %d. %s: %d/100", i+1, r.Label, r.Score)
+			if i+1 < len(result.Ranking) {
+				report += fmt.Sprintf(" (+%d)", r.Margin)
+			}
+			report += "\n"
+		}
+	}
+	if len(result.Drift) > 0 {
+		report += "\nDrift (changes not backed by the original resume):\n"
+		for _, f := range result.Drift {
+			report += fmt.Sprintf("  - %s\n", f)
+			if f.Original != "" && f.Original != f.Text {
+				report += fmt.Sprintf("      original: %s\n", f.Original)
+			}
+		}
+	}
+	return report
+}
+
+type matchOutcome struct {
+	URL       string
+	Job       *JobInfo
+	Template  string
+	Result    *MatchResult
+	OutputDir string
+	PDF       string
+}
+
+// matchJobURL runs the full match flow for a posting URL: template
+// selection, tailoring iterations, drift check, database save, output
+// files and PDF compilation. template may be empty to pick the best one.
+func matchJobURL(jobURL, company, template string, w io.Writer) (*matchOutcome, error) {
+	job, err := fetchJobDescription(jobURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching job: %v", err)
+	}
+	if job.Company == "unknown" && company != "" {
+		job.Company = company
+	}
+	if descLen := len(strings.TrimSpace(job.Description)); descLen < 200 {
+		return nil, fmt.Errorf("job description too short (%d chars)", descLen)
+	}
+
+	var ranking []templateRank
+	var bankHash string
+	if template == "" {
+		template, ranking, bankHash = selectBestTemplate(job, w)
+	}
+	resume, err := os.ReadFile(template)
+	if err != nil {
+		return nil, fmt.Errorf("reading resume: %v", err)
+	}
+
+	result, err := tailorIterations(string(resume), job, w)
+	if err != nil {
+		return nil, err
+	}
+	result.Ranking, result.Bank = ranking, bankHash
+
+	out := &matchOutcome{
+		URL:       jobURL,
+		Job:       job,
+		Template:  template,
+		Result:    result,
+		OutputDir: generateOutputDir(job),
+	}
+
+	compiled := fitMatchResult(out.OutputDir, string(resume), result, maxPages, w)
+	if err := writeMatchOutputs(out.OutputDir, job, result); err != nil {
+		return nil, fmt.Errorf("saving results: %v", err)
+	}
+	if !compiled {
+		err = compileMatchOutputs(out.OutputDir, string(resume), job, result)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "%s PDF compile failed: %v\n", color.YellowString("⚠"), err)
+	} else {
+		out.PDF = filepath.Join(out.OutputDir, "resume.pdf")
+	}
+	if _, err := writeResumeFormats(out.OutputDir, result.TailoredLatex, outputFormats); err != nil {
+		fmt.Fprintf(w, "%s Other formats: %v\n", color.YellowString("⚠"), err)
+	}
+
+	if matchStrict && len(result.Drift) > 0 {
+		return nil, fmt.Errorf("%d drift findings, see %s/report.txt", len(result.Drift), out.OutputDir)
+	}
+
+	if db != nil {
+		if err := recordMatch(jobURL, job, result, template, string(resume), out.OutputDir); err != nil {
+			fmt.Fprintf(w, "%s %v\n", color.YellowString("⚠"), err)
+		}
+	}
+
+	return out, nil
+}
+
 func generateOutputDir(job *JobInfo) string {
 	sanitize := func(s string) string {
 		s = strings.ToLower(s)
@@ -333,6 +578,7 @@ Instructions:
    - Does NOT add false information — NEVER add technologies, tools, frameworks, or skills that are not already in the original resume
    - Does NOT rewrite bullet points to mention technologies the candidate did not list
    - Keeps exact same LaTeX structure
+   - Keeps every line annotated with a "%% resumectl:lock" comment, every environment directly below one, and everything between "%% resumectl:lock-begin" and "%% resumectl:lock-end" exactly as written, comments included. A bullet below "%% resumectl:pin-top" stays first in its list.
    - If the job description appears empty or too short to analyze, return the original resume unchanged with a score of 0
 
 Respond with ONLY valid JSON (no markdown):
@@ -500,14 +746,14 @@ Instructions:
 	return apiResp.Content[0].Text, nil
 }
 
-func printIterationResult(iteration int, r *MatchResult) {
+func printIterationResult(w io.Writer, iteration int, r *MatchResult) {
 	scoreColor := color.RedString
 	if r.Score >= 80 {
 		scoreColor = color.GreenString
 	} else if r.Score >= 60 {
 		scoreColor = color.YellowString
 	}
-	fmt.Printf("  Score: %s\n", scoreColor("%d/100", r.Score))
+	fmt.Fprintf(w, "  Score: %s\n", scoreColor("%d/100", r.Score))
 }
 
 func printResult(r *MatchResult) {
@@ -538,31 +784,23 @@ func printResult(r *MatchResult) {
 	}
 }
 
-func detectFabrication(original, tailored string) []string {
-	techTerms := []string{
-		"Ruby", "Rails", "Sidekiq", "Redis", "Django", "Flask", "FastAPI",
-		"Node.js", "Express", "Angular", "Vue", "Next.js", "Svelte",
-		"C#", ".NET", "ASP.NET", "PHP", "Laravel", "Perl", "Swift",
-		"Kotlin", "Objective-C", "R ", "MATLAB", "Fortran", "Cobol",
-		"MongoDB", "DynamoDB", "CouchDB", "Neo4j", "GraphQL",
-		"RabbitMQ", "ActiveMQ", "NATS", "Pulsar", "Flink",
-		"TensorFlow", "PyTorch", "Keras", "scikit-learn",
-		"AWS Lambda", "Azure", "GCP", "Heroku",
-		"Spring Boot", "Hibernate", "Maven", "Gradle",
-		"Jenkins", "CircleCI", "TravisCI", "ArgoCD",
-		"Ansible", "Chef", "Puppet", "Consul", "Vault",
-		"Debezium", "Druid", "Pinot",
-	}
-
-	originalLower := strings.ToLower(original)
-	tailoredLower := strings.ToLower(tailored)
-
-	var fabricated []string
-	for _, term := range techTerms {
-		termLower := strings.ToLower(strings.TrimSpace(term))
-		if strings.Contains(tailoredLower, termLower) && !strings.Contains(originalLower, termLower) {
-			fabricated = append(fabricated, strings.TrimSpace(term))
-		}
-	}
-	return fabricated
+func printLockViolations(w io.Writer, violations []string) {
+	if len(violations) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s Tailoring touched locked content; the original was restored:\n", color.YellowString("⚠"))
+	for _, v := range violations {
+		fmt.Fprintf(w, "  %s %s\n", color.YellowString("•"), v)
+	}
+}
+
+func printDrift(w io.Writer, findings []DriftFinding) {
+	if len(findings) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s The tailored resume says things your original doesn't:\n", color.RedString("⚠ WARNING"))
+	for _, f := range findings {
+		fmt.Fprintf(w, "  %s %s\n", color.RedString("✗"), f)
+	}
+	fmt.Fprintln(w, "Review the tailored resume carefully before using it.")
 }
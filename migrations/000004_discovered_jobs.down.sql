@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_jobs_status;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS posted_at;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary;
+ALTER TABLE jobs DROP COLUMN IF EXISTS location;
+ALTER TABLE jobs DROP COLUMN IF EXISTS source;
@@ -0,0 +1,6 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS source TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS location TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS posted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status ON jobs(status);
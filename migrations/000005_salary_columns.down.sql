@@ -0,0 +1,3 @@
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_currency;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_max;
+ALTER TABLE jobs DROP COLUMN IF EXISTS salary_min;
@@ -0,0 +1,3 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_min INTEGER;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_max INTEGER;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS salary_currency TEXT;
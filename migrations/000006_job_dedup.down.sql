@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS job_urls;
+
+DROP INDEX IF EXISTS idx_jobs_company_key;
+
+ALTER TABLE jobs DROP COLUMN IF EXISTS fingerprint;
+ALTER TABLE jobs DROP COLUMN IF EXISTS title_key;
+ALTER TABLE jobs DROP COLUMN IF EXISTS company_key;
@@ -0,0 +1,13 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS company_key TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS title_key TEXT;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS fingerprint BIGINT;
+
+CREATE INDEX IF NOT EXISTS idx_jobs_company_key ON jobs(company_key);
+
+CREATE TABLE IF NOT EXISTS job_urls (
+    id SERIAL PRIMARY KEY,
+    job_id INTEGER REFERENCES jobs(id) ON DELETE CASCADE,
+    url TEXT UNIQUE,
+    source TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
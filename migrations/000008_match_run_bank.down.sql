@@ -0,0 +1 @@
+ALTER TABLE match_runs DROP COLUMN IF EXISTS bank_hash;
@@ -0,0 +1 @@
+ALTER TABLE match_runs ADD COLUMN IF NOT EXISTS bank_hash TEXT;
@@ -0,0 +1,3 @@
+ALTER TABLE match_runs DROP COLUMN IF EXISTS template_margin;
+ALTER TABLE match_runs DROP COLUMN IF EXISTS runner_up_template;
+ALTER TABLE match_runs DROP COLUMN IF EXISTS chosen_template;
@@ -0,0 +1,3 @@
+ALTER TABLE match_runs ADD COLUMN IF NOT EXISTS chosen_template TEXT;
+ALTER TABLE match_runs ADD COLUMN IF NOT EXISTS runner_up_template TEXT;
+ALTER TABLE match_runs ADD COLUMN IF NOT EXISTS template_margin INTEGER;
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+type ScanDigest struct {
+	Query       string       `json:"query"`
+	Board       string       `json:"board"`
+	MinScore    int          `json:"min_score"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	Jobs        []ScanResult `json:"jobs"`
+}
+
+type Notifier interface {
+	Notify(d ScanDigest) error
+}
+
+type emailNotifier struct {
+	host, port string
+	username   string
+	password   string
+	from       string
+	to         []string
+}
+
+type webhookNotifier struct {
+	url string
+}
+
+type slackNotifier struct {
+	url string
+}
+
+// newNotifier builds a sink from environment configuration:
+//
+//	email:   SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM, NOTIFY_EMAIL_TO
+//	webhook: NOTIFY_WEBHOOK_URL
+//	slack:   SLACK_WEBHOOK_URL
+func newNotifier(kind string) (Notifier, error) {
+	switch kind {
+	case "email":
+		n := &emailNotifier{
+			host:     os.Getenv("SMTP_HOST"),
+			port:     os.Getenv("SMTP_PORT"),
+			username: os.Getenv("SMTP_USERNAME"),
+			password: os.Getenv("SMTP_PASSWORD"),
+			from:     os.Getenv("SMTP_FROM"),
+		}
+		for _, addr := range strings.Split(os.Getenv("NOTIFY_EMAIL_TO"), ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				n.to = append(n.to, addr)
+			}
+		}
+		if n.host == "" || len(n.to) == 0 {
+			return nil, fmt.Errorf("email notifications need SMTP_HOST and NOTIFY_EMAIL_TO")
+		}
+		if n.port == "" {
+			n.port = "587"
+		}
+		if n.from == "" {
+			n.from = "resumectl@localhost"
+		}
+		return n, nil
+	case "webhook":
+		url := os.Getenv("NOTIFY_WEBHOOK_URL")
+		if url == "" {
+			return nil, fmt.Errorf("webhook notifications need NOTIFY_WEBHOOK_URL")
+		}
+		return &webhookNotifier{url}, nil
+	case "slack":
+		url := os.Getenv("SLACK_WEBHOOK_URL")
+		if url == "" {
+			return nil, fmt.Errorf("slack notifications need SLACK_WEBHOOK_URL")
+		}
+		return &slackNotifier{url}, nil
+	}
+	return nil, fmt.Errorf("unknown notifier %q (available: email, webhook, slack)", kind)
+}
+
+func (d ScanDigest) subject() string {
+	noun := "jobs"
+	if len(d.Jobs) == 1 {
+		noun = "job"
+	}
+	return fmt.Sprintf("resumectl: %d new %s scoring %d+ for %q", len(d.Jobs), noun, d.MinScore, d.Query)
+}
+
+func digestSalary(j ScanResult) string {
+	if s := j.Pay.String(); s != "" {
+		return s
+	}
+	if j.Salary != "" {
+		return j.Salary
+	}
+	return "salary n/a"
+}
+
+func digestLocation(j ScanResult) string {
+	if j.Location != "" {
+		return j.Location
+	}
+	return "location n/a"
+}
+
+func (n *emailNotifier) Notify(d ScanDigest) error {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%d new jobs from %s scoring %d or higher:\r\n\r\n", len(d.Jobs), d.Board, d.MinScore)
+	for _, j := range d.Jobs {
+		fmt.Fprintf(&body, "[%d] %s - %s\r\n", j.Score, j.Company, j.Title)
+		fmt.Fprintf(&body, "     %s | %s\r\n", digestSalary(j), digestLocation(j))
+		fmt.Fprintf(&body, "     %s\r\n\r\n", j.URL)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", n.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", d.subject())
+	fmt.Fprintf(&msg, "Date: %s\r\n", d.GeneratedAt.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(body.String())
+
+	var auth smtp.Auth
+	if n.username != "" {
+		auth = smtp.PlainAuth("", n.username, n.password, n.host)
+	}
+	return smtp.SendMail(n.host+":"+n.port, auth, n.from, n.to, msg.Bytes())
+}
+
+func (n *webhookNotifier) Notify(d ScanDigest) error {
+	type webhookJob struct {
+		Title    string      `json:"title"`
+		Company  string      `json:"company"`
+		Score    int         `json:"score"`
+		Salary   string      `json:"salary"`
+		Pay      SalaryRange `json:"pay"`
+		Location string      `json:"location"`
+		Source   string      `json:"source"`
+		AgeDays  int         `json:"age_days"`
+		URL      string      `json:"url"`
+	}
+	payload := struct {
+		ScanDigest
+		Jobs []webhookJob `json:"jobs"`
+	}{ScanDigest: d}
+	for _, j := range d.Jobs {
+		payload.Jobs = append(payload.Jobs, webhookJob{j.Title, j.Company, j.Score, j.Salary, j.Pay, j.Location, j.Source, j.AgeDays, j.URL})
+	}
+	return postJSON(n.url, payload)
+}
+
+func (n *slackNotifier) Notify(d ScanDigest) error {
+	var text strings.Builder
+	fmt.Fprintf(&text, "*%s*\n", d.subject())
+	for _, j := range d.Jobs {
+		fmt.Fprintf(&text, "• *%d* <%s|%s — %s> · %s · %s\n",
+			j.Score, j.URL, slackEscape(j.Company), slackEscape(j.Title), slackEscape(digestSalary(j)), slackEscape(digestLocation(j)))
+	}
+	return postJSON(n.url, map[string]string{"text": text.String()})
+}
+
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func postJSON(url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("webhook returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// newScanResults keeps results at or above minScore whose URL is not already
+// stored as a job. Jobs that were sent in a digest are stored as discovered,
+// so they aren't sent again. Without a database every result counts as new.
+func newScanResults(jobs []ScanResult, minScore int) []ScanResult {
+	var fresh []ScanResult
+	for _, j := range jobs {
+		if j.Score < minScore || j.URL == "" {
+			continue
+		}
+		if db != nil && jobKnown(j) {
+			continue
+		}
+		fresh = append(fresh, j)
+	}
+	return fresh
+}
+
+func jobKnown(j ScanResult) bool {
+	for _, u := range append([]string{j.URL}, j.AltURLs...) {
+		if _, err := jobIDForURL(u); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// sendScanDigest delivers d to every sink in kinds and reports whether any
+// of them accepted it.
+func sendScanDigest(kinds string, d ScanDigest) bool {
+	sent := false
+	for _, kind := range strings.Split(kinds, ",") {
+		kind = strings.TrimSpace(kind)
+		if kind == "" {
+			continue
+		}
+		n, err := newNotifier(kind)
+		if err == nil {
+			err = n.Notify(d)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: %s notification failed: %v\n", kind, err)
+			continue
+		}
+		fmt.Fprintf(scanLog, "  Sent %s digest with %d jobs\n", kind, len(d.Jobs))
+		sent = true
+	}
+	return sent
+}
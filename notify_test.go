@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var digestFixture = ScanDigest{
+	Query:       "data engineer",
+	Board:       "all",
+	MinScore:    80,
+	GeneratedAt: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC),
+	Jobs: []ScanResult{{
+		Title:       "Data Engineer",
+		Company:     "Acme & Co",
+		URL:         "https://example.com/jobs/1",
+		Location:    "Remote - US",
+		Pay:         SalaryRange{Min: 150000, Max: 190000, Currency: "USD", Period: "year"},
+		Description: "long description",
+		Score:       91,
+	}},
+}
+
+func captureRequest(t *testing.T) (*httptest.Server, <-chan []byte) {
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+	}))
+	t.Cleanup(srv.Close)
+	return srv, bodies
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	srv, bodies := captureRequest(t)
+	if err := (&webhookNotifier{srv.URL}).Notify(digestFixture); err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Query string `json:"query"`
+		Jobs  []struct {
+			Score    int    `json:"score"`
+			Location string `json:"location"`
+			URL      string `json:"url"`
+			Pay      struct {
+				Max float64 `json:"max"`
+			} `json:"pay"`
+			Description string `json:"description"`
+		} `json:"jobs"`
+	}
+	if err := json.Unmarshal(<-bodies, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Query != "data engineer" || len(got.Jobs) != 1 {
+		t.Fatalf("payload = %+v", got)
+	}
+	j := got.Jobs[0]
+	if j.Score != 91 || j.Location != "Remote - US" || j.URL != "https://example.com/jobs/1" || j.Pay.Max != 190000 || j.Description != "" {
+		t.Errorf("job = %+v", j)
+	}
+}
+
+func TestSlackNotifier(t *testing.T) {
+	srv, bodies := captureRequest(t)
+	if err := (&slackNotifier{srv.URL}).Notify(digestFixture); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]string
+	json.Unmarshal(<-bodies, &got)
+	want := "• *91* <https://example.com/jobs/1|Acme &amp; Co — Data Engineer> · $150k–$190k · Remote - US"
+	if !strings.Contains(got["text"], want) {
+		t.Errorf("text = %q, want line %q", got["text"], want)
+	}
+}
+
+func TestEmailNotifier(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(s string) { io.WriteString(conn, s+"\r\n") }
+
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					received <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 go ahead")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	n := &emailNotifier{host: host, port: port, from: "scan@example.com", to: []string{"me@example.com"}}
+	if err := n.Notify(digestFixture); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-received
+	for _, want := range []string{
+		"Subject: resumectl: 1 new job scoring 80+ for \"data engineer\"",
+		"[91] Acme & Co - Data Engineer",
+		"$150k–$190k | Remote - US",
+		"https://example.com/jobs/1",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message missing %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestNewScanResultsWithoutDB(t *testing.T) {
+	jobs := []ScanResult{{URL: "a", Score: 90}, {URL: "b", Score: 70}, {URL: "", Score: 95}}
+	got := newScanResults(jobs, 80)
+	if len(got) != 1 || got[0].URL != "a" {
+		t.Errorf("newScanResults = %+v", got)
+	}
+}
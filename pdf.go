@@ -3,7 +3,6 @@ package main
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 
 	"github.com/fatih/color"
@@ -35,24 +34,13 @@ func runPdf(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	clsFiles, _ := filepath.Glob("*.cls")
-	for _, cls := range clsFiles {
-		src, err := os.ReadFile(cls)
-		if err != nil {
-			continue
-		}
-		dst := filepath.Join(dir, filepath.Base(cls))
-		os.WriteFile(dst, src, 0644)
-	}
-
 	fmt.Printf("Compiling %s...\n", resumeTexPath)
-	execCmd := exec.Command("tectonic", texFile)
-	execCmd.Dir = dir
-	execCmd.Stdout = os.Stdout
-	execCmd.Stderr = os.Stderr
-
-	if err := execCmd.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error running tectonic: %v\n", err)
+	fixes, err := compileWithRepair(dir, texFile)
+	for _, f := range fixes {
+		fmt.Printf("  %s fixed %s\n", color.YellowString("⚠"), f)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error compiling: %v\n", err)
 		os.Exit(1)
 	}
 
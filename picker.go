@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+type pickerAction int
+
+const (
+	pickerNone pickerAction = iota
+	pickerPreview
+	pickerRun
+	pickerQuit
+)
+
+type picker struct {
+	jobs    []ScanResult
+	cursor  int
+	offset  int
+	marked  map[int]bool
+	preview bool
+	rows    int
+	cols    int
+}
+
+func newPicker(jobs []ScanResult) *picker {
+	return &picker{jobs: jobs, marked: make(map[int]bool), rows: 24, cols: 100}
+}
+
+func (p *picker) handle(key string) pickerAction {
+	switch key {
+	case "up", "k":
+		p.move(-1)
+	case "down", "j":
+		p.move(1)
+	case "pgup":
+		p.move(-p.listHeight())
+	case "pgdn":
+		p.move(p.listHeight())
+	case "home", "g":
+		p.move(-len(p.jobs))
+	case "end", "G":
+		p.move(len(p.jobs))
+	case "space", "x":
+		p.marked[p.cursor] = !p.marked[p.cursor]
+		if !p.marked[p.cursor] {
+			delete(p.marked, p.cursor)
+		}
+		p.move(1)
+	case "a":
+		if len(p.marked) == len(p.jobs) {
+			p.marked = make(map[int]bool)
+		} else {
+			for i := range p.jobs {
+				p.marked[i] = true
+			}
+		}
+	case "enter", "p":
+		p.preview = !p.preview
+		p.move(0)
+		if p.preview {
+			return pickerPreview
+		}
+	case "r":
+		if len(p.marked) == 0 {
+			p.marked[p.cursor] = true
+		}
+		return pickerRun
+	case "q", "esc", "ctrl-c":
+		return pickerQuit
+	}
+	return pickerNone
+}
+
+func (p *picker) move(delta int) {
+	p.cursor += delta
+	if p.cursor >= len(p.jobs) {
+		p.cursor = len(p.jobs) - 1
+	}
+	if p.cursor < 0 {
+		p.cursor = 0
+	}
+	height := p.listHeight()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+height {
+		p.offset = p.cursor - height + 1
+	}
+}
+
+func (p *picker) listHeight() int {
+	height := p.rows - 3
+	if p.preview {
+		height = (p.rows - 3) / 2
+	}
+	if height < 1 {
+		height = 1
+	}
+	return height
+}
+
+// selected returns the marked jobs in display order.
+func (p *picker) selected() []ScanResult {
+	var out []ScanResult
+	for i, j := range p.jobs {
+		if p.marked[i] {
+			out = append(out, j)
+		}
+	}
+	return out
+}
+
+func (p *picker) render(w io.Writer) {
+	var b strings.Builder
+	b.WriteString("\033[H\033[2J")
+	b.WriteString(color.New(color.Bold).Sprintf("%d jobs, %d marked", len(p.jobs), len(p.marked)))
+	b.WriteString(color.HiBlackString("   ↑/↓ move  space mark  a all  p preview  r run match  q quit"))
+	b.WriteString("\r\n\r\n")
+
+	titleWidth := p.cols - 48
+	if titleWidth < 20 {
+		titleWidth = 20
+	}
+	height := p.listHeight()
+	for i := p.offset; i < len(p.jobs) && i < p.offset+height; i++ {
+		j := p.jobs[i]
+		cursor, mark := "  ", "[ ]"
+		if i == p.cursor {
+			cursor = color.CyanString("> ")
+		}
+		if p.marked[i] {
+			mark = color.GreenString("[x]")
+		}
+		salary := j.Pay.String()
+		if salary == "" {
+			salary = "-"
+		}
+		fmt.Fprintf(&b, "%s%s %3d %-12s %-22s %s\r\n", cursor, mark, j.Score,
+			truncate(salary, 12), truncate(j.Company, 22), truncate(j.Title, titleWidth))
+	}
+
+	if p.preview && len(p.jobs) > 0 {
+		j := p.jobs[p.cursor]
+		b.WriteString(strings.Repeat("─", p.cols) + "\r\n")
+		fmt.Fprintf(&b, "%s\r\n", color.New(color.Bold).Sprintf("%s — %s", j.Company, j.Title))
+		fmt.Fprintf(&b, "%s\r\n", color.HiBlackString("%s  %s  %s", j.Location, j.Source, j.URL))
+		desc := j.Description
+		if desc == "" {
+			desc = "(no description available)"
+		}
+		lines := wrapText(desc, p.cols)
+		if max := p.rows - height - 6; len(lines) > max && max > 0 {
+			lines = lines[:max]
+		}
+		b.WriteString(strings.Join(lines, "\r\n"))
+	}
+
+	io.WriteString(w, b.String())
+}
+
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, para := range strings.Split(text, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			continue
+		}
+		line := words[0]
+		for _, w := range words[1:] {
+			if len([]rune(line))+1+len([]rune(w)) > width {
+				lines = append(lines, line)
+				line = w
+				continue
+			}
+			line += " " + w
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func readKey(r *bufio.Reader) (string, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case 3:
+		return "ctrl-c", nil
+	case '\r', '\n':
+		return "enter", nil
+	case ' ':
+		return "space", nil
+	case 27:
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		next, _ := r.ReadByte()
+		if next != '[' && next != 'O' {
+			return "esc", nil
+		}
+		code, _ := r.ReadByte()
+		switch code {
+		case 'A':
+			return "up", nil
+		case 'B':
+			return "down", nil
+		case 'H':
+			return "home", nil
+		case 'F':
+			return "end", nil
+		case '5', '6':
+			r.ReadByte() // trailing '~'
+			if code == '5' {
+				return "pgup", nil
+			}
+			return "pgdn", nil
+		}
+		return "", nil
+	}
+	return string(c), nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+func terminalSize() (rows, cols int) {
+	rows, cols = 24, 100
+	out, err := stty("size")
+	if err != nil {
+		return
+	}
+	fields := strings.Fields(out)
+	if len(fields) == 2 {
+		if r, err := strconv.Atoi(fields[0]); err == nil && r > 0 {
+			rows = r
+		}
+		if c, err := strconv.Atoi(fields[1]); err == nil && c > 0 {
+			cols = c
+		}
+	}
+	return
+}
+
+// pickJobs shows the interactive picker and returns the jobs marked for
+// matching, or nil if the user quit.
+func pickJobs(jobs []ScanResult) ([]ScanResult, error) {
+	state, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("--interactive needs a terminal: %v", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+	fmt.Print("\033[?25l")
+	defer func() {
+		stty(state)
+		fmt.Print("\033[?25h\033[H\033[2J")
+	}()
+
+	p := newPicker(jobs)
+	p.rows, p.cols = terminalSize()
+	in := bufio.NewReader(os.Stdin)
+
+	for {
+		p.render(os.Stdout)
+		key, err := readKey(in)
+		if err != nil {
+			return nil, err
+		}
+		switch p.handle(key) {
+		case pickerQuit:
+			return nil, nil
+		case pickerRun:
+			return p.selected(), nil
+		case pickerPreview:
+			j := &p.jobs[p.cursor]
+			if j.Description == "" && j.URL != "" {
+				p.render(os.Stdout)
+				io.WriteString(os.Stdout, "\r\nFetching description...")
+				if info, err := fetchJobDescription(j.URL); err == nil {
+					j.Description = strings.TrimSpace(info.Description)
+				}
+			}
+		}
+	}
+}
+
+func runInteractiveMatch(jobs []ScanResult, template string) {
+	selected, err := pickJobs(jobs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(selected) == 0 {
+		return
+	}
+
+	if err := InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not init database: %v\n", err)
+	}
+
+	type matchRow struct {
+		job     ScanResult
+		outcome *matchOutcome
+		err     error
+	}
+	var rows []matchRow
+
+	for i, j := range selected {
+		fmt.Printf("\n%s [%d/%d] %s — %s\n", color.CyanString("→"), i+1, len(selected), j.Company, j.Title)
+		outcome, err := matchJobURL(j.URL, j.Company, template, os.Stdout)
+		if err != nil {
+			fmt.Printf("  %s %v\n", color.RedString("✗"), err)
+		} else {
+			fmt.Printf("  %s %d/100 → %s\n", color.GreenString("✓"), outcome.Result.Score, outcome.OutputDir)
+		}
+		rows = append(rows, matchRow{j, outcome, err})
+	}
+
+	fmt.Println()
+	fmt.Println(color.New(color.Bold, color.Underline).Sprint("Summary"))
+	fmt.Printf("%-6s %-20s %-30s %-4s %s\n", "Score", "Company", "Title", "PDF", "Output")
+	fmt.Println(strings.Repeat("─", 110))
+	for _, r := range rows {
+		if r.err != nil {
+			fmt.Printf("%-6s %-20s %-30s %-4s %s\n", color.RedString("fail"), truncate(r.job.Company, 20), truncate(r.job.Title, 30), "-", r.err)
+			continue
+		}
+		pdf := "no"
+		if r.outcome.PDF != "" {
+			pdf = "yes"
+		}
+		warn := ""
+		if n := len(r.outcome.Result.Drift); n > 0 {
+			warn = color.RedString("  ⚠ %d drift findings", n)
+		}
+		fmt.Printf("%-6d %-20s %-30s %-4s %s%s\n", r.outcome.Result.Score, truncate(r.job.Company, 20), truncate(r.job.Title, 30), pdf, r.outcome.OutputDir, warn)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+)
+
+func TestPickerHandle(t *testing.T) {
+	jobs := []ScanResult{{Title: "A"}, {Title: "B"}, {Title: "C"}}
+	p := newPicker(jobs)
+
+	p.handle("up")
+	if p.cursor != 0 {
+		t.Errorf("cursor moved above first row: %d", p.cursor)
+	}
+	p.handle("space")
+	p.handle("down")
+	p.handle("space")
+	if p.cursor != 2 {
+		t.Errorf("cursor = %d, want 2", p.cursor)
+	}
+	if got := p.selected(); len(got) != 2 || got[0].Title != "A" || got[1].Title != "C" {
+		t.Errorf("selected = %+v", got)
+	}
+
+	p.handle("a")
+	if len(p.selected()) != 3 {
+		t.Errorf("mark all: %d selected", len(p.selected()))
+	}
+	p.handle("a")
+	if len(p.selected()) != 0 {
+		t.Errorf("clear all: %d selected", len(p.selected()))
+	}
+
+	if p.handle("p") != pickerPreview || !p.preview {
+		t.Error("p should open the preview")
+	}
+	if p.handle("r") != pickerRun {
+		t.Error("r should run")
+	}
+	if got := p.selected(); len(got) != 1 || got[0].Title != "C" {
+		t.Errorf("run with nothing marked should select cursor row, got %+v", got)
+	}
+	if p.handle("q") != pickerQuit {
+		t.Error("q should quit")
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("\x1b[A\x1b[B\x1b[6~ \rq"))
+	want := []string{"up", "down", "pgdn", "space", "enter", "q"}
+	for _, w := range want {
+		got, err := readKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != w {
+			t.Errorf("readKey = %q, want %q", got, w)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	got := wrapText("one two three four\n\nfive", 9)
+	want := []string{"one two", "three", "four", "five"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapText = %q, want %q", got, want)
+	}
+}
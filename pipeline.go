@@ -45,6 +45,7 @@ func printFunnel() {
 		FROM jobs
 		GROUP BY status
 		ORDER BY CASE status
+			WHEN 'discovered' THEN 0
 			WHEN 'new' THEN 1
 			WHEN 'applied' THEN 2
 			WHEN 'screening' THEN 3
@@ -85,6 +86,8 @@ func printFunnel() {
 		barStr := strings.Repeat("█", barLen)
 		colorFn := color.New(color.FgWhite).SprintFunc()
 		switch r.status {
+		case "discovered":
+			colorFn = color.New(color.FgHiBlack).SprintFunc()
 		case "new":
 			colorFn = color.New(color.FgBlue).SprintFunc()
 		case "applied":
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// hostRates are the default request rates (per second) for each host the
+// scan pipeline talks to. Unlisted hosts use defaultHostRate.
+var hostRates = map[string]float64{
+	"web3.career":       2,
+	"remoteok.com":      1,
+	"serpapi.com":       2,
+	"api.anthropic.com": 8,
+}
+
+const defaultHostRate = 2
+
+type hostLimiter struct {
+	mu       sync.Mutex
+	next     map[string]time.Time
+	override float64
+}
+
+var scanLimiter = &hostLimiter{next: make(map[string]time.Time)}
+
+func (l *hostLimiter) interval(host string) time.Duration {
+	rate := l.override
+	if rate <= 0 {
+		rate = hostRates[host]
+	}
+	if rate <= 0 {
+		rate = defaultHostRate
+	}
+	return time.Duration(float64(time.Second) / rate)
+}
+
+// wait blocks until a request to host is allowed or ctx is cancelled.
+func (l *hostLimiter) wait(ctx context.Context, host string) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next[host]
+	if at.Before(now) {
+		at = now
+	}
+	l.next[host] = at.Add(l.interval(host))
+	l.mu.Unlock()
+
+	delay := time.Until(at)
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// limitedDo sends req through the per-host rate limiter, bound to ctx.
+func limitedDo(ctx context.Context, client *http.Client, req *http.Request) (*http.Response, error) {
+	if err := scanLimiter.wait(ctx, req.URL.Hostname()); err != nil {
+		return nil, err
+	}
+	return client.Do(req.WithContext(ctx))
+}
+
+// runPool calls fn for every index in [0, count) using at most workers
+// goroutines. Once ctx is cancelled no new indices are started; runPool
+// returns after in-flight calls finish.
+func runPool(ctx context.Context, workers, count int, fn func(i int)) {
+	if workers < 1 {
+		workers = 1
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				fn(i)
+			}
+		}()
+	}
+
+dispatch:
+	for i := 0; i < count; i++ {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indices <- i:
+		}
+	}
+	close(indices)
+	wg.Wait()
+}
+
+type scanProgress struct {
+	mu    sync.Mutex
+	w     io.Writer
+	label string
+	total int
+	done  int
+}
+
+func newScanProgress(w io.Writer, label string, total int) *scanProgress {
+	p := &scanProgress{w: w, label: label, total: total}
+	p.print()
+	return p
+}
+
+func (p *scanProgress) inc() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	p.print()
+}
+
+func (p *scanProgress) print() {
+	fmt.Fprintf(p.w, "\r%s %d/%d", p.label, p.done, p.total)
+}
+
+func (p *scanProgress) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintln(p.w)
+}
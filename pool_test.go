@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunPool(t *testing.T) {
+	var running, peak int32
+	done := make([]bool, 20)
+	runPool(context.Background(), 3, len(done), func(i int) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		done[i] = true
+		atomic.AddInt32(&running, -1)
+	})
+
+	for i, ok := range done {
+		if !ok {
+			t.Errorf("index %d not processed", i)
+		}
+	}
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want <= 3", peak)
+	}
+}
+
+func TestRunPoolCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	runPool(ctx, 2, 100, func(i int) {
+		if atomic.AddInt32(&calls, 1) == 4 {
+			cancel()
+		}
+	})
+	if calls >= 100 {
+		t.Errorf("cancel did not stop dispatch: %d calls", calls)
+	}
+}
+
+func TestHostLimiter(t *testing.T) {
+	l := &hostLimiter{next: make(map[string]time.Time), override: 50}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background(), "example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("3 requests at 50/s took %v, want >= 40ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.next["slow.example"] = time.Now().Add(time.Hour)
+	if err := l.wait(ctx, "slow.example"); err == nil {
+		t.Error("wait should fail on a cancelled context")
+	}
+}
+
+func TestSortByScoreDeterministic(t *testing.T) {
+	a := []ScanResult{
+		{Company: "B", Title: "x", Score: 80},
+		{Company: "A", Title: "y", Score: 80},
+		{Company: "C", Title: "z", Score: 90},
+		{Company: "A", Title: "a", Score: 80},
+	}
+	b := []ScanResult{a[3], a[2], a[1], a[0]}
+	sortByScore(a)
+	sortByScore(b)
+	for i := range a {
+		if a[i].Company != b[i].Company || a[i].Title != b[i].Title {
+			t.Fatalf("order differs at %d: %+v vs %+v", i, a[i], b[i])
+		}
+	}
+	if a[0].Company != "C" || a[1].Title != "a" || a[3].Company != "B" {
+		t.Errorf("sorted = %+v", a)
+	}
+}
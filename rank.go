@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// templateRank is one template's place in a ranking for a job.
+type templateRank struct {
+	Label  string `json:"label"`
+	Path   string `json:"-"`
+	Score  int    `json:"score"`
+	Margin int    `json:"margin"` // points ahead of the next template
+}
+
+type cachedRank struct {
+	Hash  string `json:"hash"`
+	Score int    `json:"score"`
+}
+
+// rankTemplates orders templates best first for job. All templates are
+// ranked together in one request, and the result is cached by the job and
+// the templates' contents. When that fails each template is scored on its
+// own instead.
+func rankTemplates(templates []Template, job *JobInfo) ([]templateRank, error) {
+	sources := make([]string, len(templates))
+	for i, t := range templates {
+		src, err := os.ReadFile(t.Path)
+		if err != nil {
+			return nil, err
+		}
+		sources[i] = string(src)
+	}
+
+	key := rankingCacheKey(sources, job)
+	if ranking := loadCachedRanking(key, templates, sources); ranking != nil {
+		return ranking, nil
+	}
+
+	order, scores, err := rankTemplatesListwise(templates, sources, job)
+	if err == nil {
+		ordered := make([]Template, len(order))
+		for i, idx := range order {
+			ordered[i] = templates[idx]
+		}
+		ranking := orderRanking(ordered, scores)
+		saveCachedRanking(key, ranking, templates, sources)
+		return ranking, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "  Warning: could not rank templates together, scoring each: %v\n", err)
+	scores = make([]int, len(templates))
+	scored := 0
+	for i, t := range templates {
+		score, err := scoreTemplate(sources[i], job.Title, job.Description, t.Meta)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: could not score %s: %v\n", t.Label, err)
+			continue
+		}
+		scores[i] = score
+		scored++
+	}
+	if scored == 0 {
+		return nil, fmt.Errorf("no template could be scored")
+	}
+	return orderRanking(templates, scores), nil
+}
+
+// orderRanking sorts templates by score, keeping their order on ties, and
+// works out each one's margin over the next.
+func orderRanking(templates []Template, scores []int) []templateRank {
+	ranking := make([]templateRank, len(templates))
+	for i, t := range templates {
+		ranking[i] = templateRank{Label: t.Label, Path: t.Path, Score: scores[i]}
+	}
+	sort.SliceStable(ranking, func(i, j int) bool { return ranking[i].Score > ranking[j].Score })
+	for i := 0; i+1 < len(ranking); i++ {
+		ranking[i].Margin = ranking[i].Score - ranking[i+1].Score
+	}
+	return ranking
+}
+
+func rankingCacheKey(sources []string, job *JobInfo) string {
+	hashes := make([]string, len(sources))
+	for i, src := range sources {
+		hashes[i] = contentHash(src)
+	}
+	sort.Strings(hashes)
+	return contentHash(job.Title + "\n" + job.Description + "\n" + strings.Join(hashes, ","))
+}
+
+func rankingCachePath(key string) string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".resumectl", "cache", "template-rankings", key+".json")
+}
+
+// loadCachedRanking returns the cached ranking for key, or nil when there is
+// none or it doesn't cover exactly these templates.
+func loadCachedRanking(key string, templates []Template, sources []string) []templateRank {
+	data, err := os.ReadFile(rankingCachePath(key))
+	if err != nil {
+		return nil
+	}
+	var cached []cachedRank
+	if json.Unmarshal(data, &cached) != nil || len(cached) != len(templates) {
+		return nil
+	}
+	byHash := make(map[string]int)
+	for i, src := range sources {
+		byHash[contentHash(src)] = i
+	}
+	ordered := make([]Template, len(cached))
+	scores := make([]int, len(cached))
+	for i, c := range cached {
+		idx, ok := byHash[c.Hash]
+		if !ok {
+			return nil
+		}
+		delete(byHash, c.Hash)
+		ordered[i], scores[i] = templates[idx], c.Score
+	}
+	return orderRanking(ordered, scores)
+}
+
+func saveCachedRanking(key string, ranking []templateRank, templates []Template, sources []string) {
+	hashes := make(map[string]string)
+	for i, t := range templates {
+		hashes[t.Path] = contentHash(sources[i])
+	}
+	cached := make([]cachedRank, len(ranking))
+	for i, r := range ranking {
+		cached[i] = cachedRank{hashes[r.Path], r.Score}
+	}
+	data, _ := json.Marshal(cached)
+	path := rankingCachePath(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err == nil {
+		os.WriteFile(path, data, 0644)
+	}
+}
+
+// rankTemplatesListwise asks for all templates to be ranked in one prompt.
+// It returns their indexes best first and the score of each.
+func rankTemplatesListwise(templates []Template, sources []string, job *JobInfo) (order, scores []int, err error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, nil, fmt.Errorf("ANTHROPIC_API_KEY not set")
+	}
+
+	var sb strings.Builder
+	for i, t := range templates {
+		sb.WriteString(fmt.Sprintf("=== RESUME %d (focus: %s) ===\n", i+1, t.Meta.Focus))
+		if targets := strings.TrimSpace(t.Meta.targets()); targets != "" {
+			sb.WriteString(targets + "\n")
+		}
+		sb.WriteString(sources[i] + "\n\n")
+	}
+
+	prompt := fmt.Sprintf(`You are ranking %d resume variants for a job application.
+Job title: "%s"
+
+Each resume is labeled with its focus area. Rank them based on:
+1. Role type alignment — does the resume's focus match the job's primary domain? (weighted heavily)
+2. Skill keyword overlap
+3. How the experience is framed
+
+Score every resume 0-100 and list all %d of them, best first, each exactly once. Give different scores unless two resumes are genuinely equal.
+Output ONLY: {"ranking":[{"resume":N,"score":S},...]} where N is the resume number (1 to %d).
+
+%s
+=== JOB DESCRIPTION ===
+%s`, len(templates), job.Title, len(templates), len(templates), sb.String(), job.Description)
+
+	reqBody := map[string]interface{}{
+		"model":      "claude-haiku-4-5-20251001",
+		"max_tokens": 50 + 20*len(templates),
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	jsonBody, _ := json.Marshal(reqBody)
+
+	req, _ := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, nil, err
+	}
+
+	if len(apiResp.Content) == 0 {
+		return nil, nil, fmt.Errorf("empty response")
+	}
+	return parseTemplateRanking(apiResp.Content[0].Text, len(templates))
+}
+
+// parseTemplateRanking reads a {"ranking":[...]} answer for n resumes. It
+// returns the resume indexes in the order given and the score of each.
+// Every resume must appear exactly once.
+func parseTemplateRanking(text string, n int) (order, scores []int, err error) {
+	text = strings.TrimSpace(text)
+	start := strings.Index(text, "{")
+	end := strings.LastIndex(text, "}")
+	if start == -1 || end == -1 || end <= start {
+		return nil, nil, fmt.Errorf("no JSON in response")
+	}
+
+	var result struct {
+		Ranking []struct {
+			Resume int `json:"resume"`
+			Score  int `json:"score"`
+		} `json:"ranking"`
+	}
+	if err := json.Unmarshal([]byte(text[start:end+1]), &result); err != nil {
+		return nil, nil, fmt.Errorf("parse error: %v", err)
+	}
+
+	seen := make([]bool, n)
+	for _, r := range result.Ranking {
+		idx := r.Resume - 1
+		if idx < 0 || idx >= n {
+			return nil, nil, fmt.Errorf("ranking names resume %d of %d", r.Resume, n)
+		}
+		if seen[idx] {
+			return nil, nil, fmt.Errorf("ranking lists resume %d twice", r.Resume)
+		}
+		seen[idx] = true
+		order = append(order, idx)
+		scores = append(scores, min(max(r.Score, 0), 100))
+	}
+	if len(order) != n {
+		return nil, nil, fmt.Errorf("ranking lists %d of %d resumes", len(order), n)
+	}
+	return order, scores, nil
+}
+
+func printRanking(w io.Writer, ranking []templateRank) {
+	for i, r := range ranking {
+		margin := ""
+		if i+1 < len(ranking) {
+			margin = fmt.Sprintf(" (+%d)", r.Margin)
+		}
+		fmt.Fprintf(w, "  %d. %s: %s%s\n", i+1, r.Label, color.CyanString("%d/100", r.Score), margin)
+	}
+}
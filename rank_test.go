@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseTemplateRanking(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		n      int
+		order  []int
+		scores []int
+		err    string
+	}{
+		{"three way", `{"ranking":[{"resume":2,"score":88},{"resume":3,"score":80},{"resume":1,"score":61}]}`, 3, []int{1, 2, 0}, []int{88, 80, 61}, ""},
+		{"surrounding text", "Here you go:\n```json\n{\"ranking\":[{\"resume\":1,\"score\":140},{\"resume\":2,\"score\":-3}]}\n```", 2, []int{0, 1}, []int{100, 0}, ""},
+		{"missing resume", `{"ranking":[{"resume":1,"score":70},{"resume":2,"score":60}]}`, 3, nil, nil, "lists 2 of 3"},
+		{"duplicate", `{"ranking":[{"resume":1,"score":70},{"resume":1,"score":60},{"resume":2,"score":50}]}`, 3, nil, nil, "twice"},
+		{"out of range", `{"ranking":[{"resume":4,"score":70}]}`, 3, nil, nil, "resume 4 of 3"},
+		{"no json", `Resume 2 is best`, 3, nil, nil, "no JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, scores, err := parseTemplateRanking(tt.text, tt.n)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Errorf("err = %v, want %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(order, tt.order) || !slices.Equal(scores, tt.scores) {
+				t.Errorf("got %v %v, want %v %v", order, scores, tt.order, tt.scores)
+			}
+		})
+	}
+}
+
+func TestOrderRanking(t *testing.T) {
+	templates := []Template{{Label: "backend"}, {Label: "data"}, {Label: "ml"}, {Label: "platform"}}
+	ranking := orderRanking(templates, []int{70, 85, 70, 40})
+	var got []string
+	for _, r := range ranking {
+		got = append(got, r.Label)
+	}
+	if strings.Join(got, ",") != "data,backend,ml,platform" {
+		t.Errorf("order = %v", got)
+	}
+	var margins []int
+	for _, r := range ranking {
+		margins = append(margins, r.Margin)
+	}
+	if !slices.Equal(margins, []int{15, 0, 30, 0}) {
+		t.Errorf("margins = %v", margins)
+	}
+}
+
+func TestRankingCache(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	var templates []Template
+	var sources []string
+	for _, label := range []string{"backend", "data", "ml"} {
+		src := strings.Replace(sampleResume, "Staff Data Engineer", label, 1)
+		path := filepath.Join(home, label+".tex")
+		if err := os.WriteFile(path, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		templates = append(templates, Template{Label: label, Path: path})
+		sources = append(sources, src)
+	}
+	job := &JobInfo{Title: "Data Engineer", Description: "Kafka and Snowflake"}
+	key := rankingCacheKey(sources, job)
+
+	if loadCachedRanking(key, templates, sources) != nil {
+		t.Fatal("ranking cached before it was saved")
+	}
+	saveCachedRanking(key, orderRanking([]Template{templates[1], templates[2], templates[0]}, []int{90, 75, 75}), templates, sources)
+
+	// The cache is keyed by content, so template order doesn't matter.
+	reversed := []Template{templates[2], templates[1], templates[0]}
+	if rankingCacheKey([]string{sources[2], sources[1], sources[0]}, job) != key {
+		t.Fatal("cache key depends on template order")
+	}
+	cached := loadCachedRanking(key, reversed, []string{sources[2], sources[1], sources[0]})
+	if len(cached) != 3 || cached[0].Label != "data" || cached[0].Margin != 15 || cached[1].Label != "ml" || cached[2].Path != templates[0].Path {
+		t.Errorf("cached ranking = %+v", cached)
+	}
+
+	ranking, err := rankTemplates(templates, job)
+	if err != nil || ranking[0].Label != "data" {
+		t.Errorf("rankTemplates didn't use the cache: %+v, %v", ranking, err)
+	}
+
+	if rankingCacheKey(sources, &JobInfo{Title: "ML Engineer"}) == key {
+		t.Error("cache key ignores the job")
+	}
+}
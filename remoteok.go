@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -9,16 +10,19 @@ import (
 )
 
 type RemoteOKJob struct {
-	ID       string   `json:"id"`
-	Position string   `json:"position"`
-	Company  string   `json:"company"`
-	Location string   `json:"location"`
-	Tags     []string `json:"tags"`
-	URL      string   `json:"url"`
-	Epoch    int64    `json:"epoch"`
+	ID          string   `json:"id"`
+	Position    string   `json:"position"`
+	Company     string   `json:"company"`
+	Location    string   `json:"location"`
+	Tags        []string `json:"tags"`
+	URL         string   `json:"url"`
+	Epoch       int64    `json:"epoch"`
+	SalaryMin   int      `json:"salary_min"`
+	SalaryMax   int      `json:"salary_max"`
+	Description string   `json:"description"`
 }
 
-func scanRemoteOK(keywords []string, maxAgeDays int) ([]ScanResult, error) {
+func scanRemoteOK(ctx context.Context, keywords []string, maxAgeDays int) ([]ScanResult, error) {
 	req, err := http.NewRequest("GET", "https://remoteok.com/api", nil)
 	if err != nil {
 		return nil, err
@@ -26,7 +30,7 @@ func scanRemoteOK(keywords []string, maxAgeDays int) ([]ScanResult, error) {
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)")
 
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := limitedDo(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -81,13 +85,25 @@ func scanRemoteOK(keywords []string, maxAgeDays int) ([]ScanResult, error) {
 			loc = "Remote"
 		}
 
+		var pay SalaryRange
+		if j.SalaryMax > 0 {
+			pay = SalaryRange{Min: float64(j.SalaryMin), Max: float64(j.SalaryMax), Currency: "USD", Period: "year"}
+			if pay.Min == 0 {
+				pay.Min = pay.Max
+			}
+		}
+
 		results = append(results, ScanResult{
-			Title:    j.Position,
-			Company:  j.Company,
-			URL:      j.URL,
-			Age:      ageStr,
-			AgeDays:  int(age.Hours() / 24),
-			Location: loc,
+			Title:       j.Position,
+			Company:     j.Company,
+			URL:         j.URL,
+			Age:         ageStr,
+			AgeDays:     int(age.Hours() / 24),
+			Location:    loc,
+			Salary:      pay.String(),
+			Pay:         pay,
+			Source:      "remoteok",
+			Description: stripHTML(j.Description),
 		})
 	}
 
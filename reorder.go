@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// reorderPlan is what the model returns in --mode reorder: only IDs and
+// existing skill items, never text.
+type reorderPlan struct {
+	Score         int                 `json:"score"`
+	StrongMatches []string            `json:"strong_matches"`
+	Gaps          []string            `json:"gaps"`
+	Order         map[string][]string `json:"order"`
+	DropBullets   []string            `json:"drop_bullets"`
+	Skills        []struct {
+		ID    string   `json:"id"`
+		Items []string `json:"items"`
+	} `json:"skills"`
+}
+
+// tailorResume dispatches to the tailoring mode selected with --mode.
+func tailorResume(resume, jobDescription string) (*MatchResult, error) {
+	if matchMode == "reorder" {
+		return analyzeAndReorder(resume, jobDescription)
+	}
+	return analyzeAndTailor(resume, jobDescription)
+}
+
+func describeForReorder(doc *ResumeDoc) string {
+	var b strings.Builder
+	for i, l := range doc.Lists() {
+		if len(l.Bullets) == 0 {
+			continue
+		}
+		key := listKey(l.Bullets[0].ID)
+		if role := roleForList(doc, l); role != nil {
+			fmt.Fprintf(&b, "LIST %s — %s, %s (%s)\n", key, role.Title, role.Employer, role.Dates)
+		} else {
+			fmt.Fprintf(&b, "LIST %s — list %d\n", key, i+1)
+		}
+		for _, bullet := range l.Bullets {
+			fmt.Fprintf(&b, "  %s: %s\n", bullet.ID, bullet.Text)
+		}
+	}
+	for _, r := range doc.SkillRows() {
+		fmt.Fprintf(&b, "SKILLS %s — %s: %s\n", r.ID, r.Category, strings.Join(r.Items, " | "))
+	}
+	return b.String()
+}
+
+func listKey(bulletID string) string {
+	if i := strings.LastIndex(bulletID, "."); i > 0 {
+		return bulletID[:i]
+	}
+	return bulletID
+}
+
+func roleForList(doc *ResumeDoc, l *ResumeList) *ResumeRole {
+	for _, r := range doc.Roles() {
+		if &r.ResumeList == l {
+			return r
+		}
+	}
+	return nil
+}
+
+func analyzeAndReorder(resume, jobDescription string) (*MatchResult, error) {
+	apiKey := os.Getenv("ANTHROPIC_API_KEY")
+	if apiKey == "" {
+		return nil, fmt.Errorf("ANTHROPIC_API_KEY not set")
+	}
+
+	doc, err := parseResume(resume)
+	if err != nil {
+		return nil, fmt.Errorf("parsing resume: %v", err)
+	}
+	if len(doc.Bullets()) == 0 && len(doc.SkillRows()) == 0 {
+		return nil, fmt.Errorf("no bullets or skill rows found in resume")
+	}
+
+	prompt := fmt.Sprintf(`Analyze this resume against the job description, then choose how to ORDER its existing content. You cannot write or edit any text.
+
+RESUME CONTENT (bullets and skill rows with IDs):
+%s
+
+JOB DESCRIPTION:
+%s
+
+Instructions:
+1. Score the match 0-100 based on actual skill/experience alignment
+2. Identify strong matches and gaps. Only flag a gap if the skill/experience is genuinely absent and cannot be reasonably inferred. Every point deducted must be explained by a gap.
+3. For each LIST, return its bullet IDs in the order that mirrors the job description's priorities. Only use IDs from that list.
+4. Optionally list bullet IDs to drop when they are irrelevant to this job. Never drop every bullet of a list.
+5. Return skill rows in order of relevance. For each row, list the items to keep, copied exactly from that row, most relevant first. Drop items that the job neither mentions nor implies, but keep every item that appears in the job description.
+
+Respond with ONLY valid JSON (no markdown):
+{
+  "score": <0-100>,
+  "strong_matches": ["match1", ...],
+  "gaps": ["gap1", ...],
+  "order": {"R1": ["R1.3", "R1.1", "R1.2"], ...},
+  "drop_bullets": ["R2.4", ...],
+  "skills": [{"id": "S2", "items": ["Kafka", "Spark"]}, ...]
+}`, describeForReorder(doc), jobDescription)
+
+	reqBody := map[string]interface{}{
+		"model":      modelName,
+		"max_tokens": 4000,
+		"messages": []map[string]string{
+			{"role": "user", "content": prompt},
+		},
+	}
+
+	jsonBody, _ := json.Marshal(reqBody)
+
+	req, _ := http.NewRequest("POST", "https://api.anthropic.com/v1/messages", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", apiKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	var apiResp struct {
+		Content []struct {
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, err
+	}
+
+	if len(apiResp.Content) == 0 {
+		return nil, fmt.Errorf("empty response")
+	}
+
+	text := strings.TrimSpace(apiResp.Content[0].Text)
+	if strings.HasPrefix(text, "```") {
+		if idx := strings.Index(text, "\n"); idx != -1 {
+			text = text[idx+1:]
+		}
+		if idx := strings.LastIndex(text, "```"); idx != -1 {
+			text = text[:idx]
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	var plan reorderPlan
+	if err := json.Unmarshal([]byte(text), &plan); err != nil {
+		return nil, fmt.Errorf("parse error: %v\nRaw: %s", err, text)
+	}
+
+	applyReorderPlan(doc, &plan)
+	tailored := postProcessLatex(doc.Render())
+	if err := verifyReordered(resume, tailored); err != nil {
+		return nil, err
+	}
+
+	return &MatchResult{
+		Score:         plan.Score,
+		StrongMatches: plan.StrongMatches,
+		Gaps:          filterFalseGaps(plan.Gaps, resume),
+		TailoredLatex: tailored,
+	}, nil
+}
+
+// verifyReordered rejects a reordered resume holding a bullet or skill item
+// that isn't in the original word for word.
+func verifyReordered(original, tailored string) error {
+	src, err := parseResume(original)
+	if err != nil {
+		return fmt.Errorf("parsing resume: %v", err)
+	}
+	out, err := parseResume(tailored)
+	if err != nil {
+		return fmt.Errorf("parsing reordered resume: %v", err)
+	}
+
+	bullets := make(map[string]bool)
+	for _, b := range src.Bullets() {
+		bullets[b.Text] = true
+	}
+	for _, b := range out.Bullets() {
+		if !bullets[b.Text] {
+			return fmt.Errorf("reorder produced a bullet that isn't in the resume: %q", b.Text)
+		}
+	}
+
+	items := make(map[string]bool)
+	for _, r := range src.SkillRows() {
+		for _, item := range r.Items {
+			items[item] = true
+		}
+	}
+	for _, r := range out.SkillRows() {
+		for _, item := range r.Items {
+			if !items[item] {
+				return fmt.Errorf("reorder produced a skill that isn't in the resume: %q", item)
+			}
+		}
+	}
+	return nil
+}
+
+// applyReorderPlan reorders and filters the document's existing bullets and
+// skill items. Unknown IDs and items are ignored, bullets the plan doesn't
+// mention keep their relative order after the ordered ones, and a list is
+// never emptied.
+func applyReorderPlan(doc *ResumeDoc, plan *reorderPlan) {
+	drop := make(map[string]bool)
+	for _, id := range plan.DropBullets {
+		drop[id] = true
+	}
+
+	for _, l := range doc.Lists() {
+		if len(l.Bullets) == 0 {
+			continue
+		}
+		byID := make(map[string]*Bullet)
+		for _, b := range l.Bullets {
+			byID[b.ID] = b
+		}
+
+		var ordered []*Bullet
+		used := make(map[string]bool)
+		for _, id := range plan.Order[listKey(l.Bullets[0].ID)] {
+			if b, ok := byID[id]; ok && !used[id] {
+				ordered = append(ordered, b)
+				used[id] = true
+			}
+		}
+		for _, b := range l.Bullets {
+			if !used[b.ID] {
+				ordered = append(ordered, b)
+			}
+		}
+
+		var kept []*Bullet
+		for _, b := range ordered {
+			if !drop[b.ID] {
+				kept = append(kept, b)
+			}
+		}
+		if len(kept) == 0 {
+			kept = ordered
+		}
+		l.Bullets = kept
+	}
+
+	for _, s := range doc.Sections {
+		for _, block := range s.Blocks {
+			if block.Skills != nil {
+				applySkillPlan(block.Skills, plan)
+			}
+		}
+	}
+}
+
+func applySkillPlan(table *SkillTable, plan *reorderPlan) {
+	byID := make(map[string]*SkillRow)
+	for _, r := range table.Rows {
+		byID[r.ID] = r
+	}
+
+	var ordered []*SkillRow
+	used := make(map[string]bool)
+	for _, choice := range plan.Skills {
+		row, ok := byID[choice.ID]
+		if !ok || used[choice.ID] {
+			continue
+		}
+		used[choice.ID] = true
+
+		original := make(map[string]string)
+		for _, item := range row.Items {
+			original[strings.ToLower(strings.TrimSpace(item))] = item
+		}
+		var items []string
+		seen := make(map[string]bool)
+		for _, item := range choice.Items {
+			key := strings.ToLower(strings.TrimSpace(item))
+			if orig, ok := original[key]; ok && !seen[key] {
+				items = append(items, orig)
+				seen[key] = true
+			}
+		}
+		row.Items = items
+		ordered = append(ordered, row)
+	}
+	for _, r := range table.Rows {
+		if !used[r.ID] {
+			ordered = append(ordered, r)
+		}
+	}
+	table.Rows = ordered
+}
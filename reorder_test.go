@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestApplyReorderPlan(t *testing.T) {
+	doc, err := parseResume(sampleResume)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := make(map[string]bool)
+	for _, b := range doc.Bullets() {
+		original[b.Text] = true
+	}
+
+	var plan reorderPlan
+	json.Unmarshal([]byte(`{
+		"order": {"R1": ["R1.3", "R9.9", "R1.1", "R1.3"], "R2": ["R2.2"]},
+		"drop_bullets": ["R1.2", "L1.1"],
+		"skills": [
+			{"id": "S2", "items": ["spark", "Kubernetes", "Kafka"]},
+			{"id": "S3", "items": []}
+		]
+	}`), &plan)
+	applyReorderPlan(doc, &plan)
+
+	var got []string
+	for _, b := range doc.Roles()[0].Bullets {
+		got = append(got, b.ID)
+	}
+	if strings.Join(got, ",") != "R1.3,R1.1" {
+		t.Errorf("R1 order = %v", got)
+	}
+	got = nil
+	for _, b := range doc.Roles()[1].Bullets {
+		got = append(got, b.ID)
+	}
+	if strings.Join(got, ",") != "R2.2,R2.1" {
+		t.Errorf("R2 order = %v", got)
+	}
+	if projects := doc.Lists()[2].Bullets; len(projects) != 1 {
+		t.Errorf("dropping the only bullet of a list should be ignored, got %d bullets", len(projects))
+	}
+
+	rows := doc.SkillRows()
+	if rows[0].ID != "S2" || strings.Join(rows[0].Items, ",") != "Spark,Kafka" {
+		t.Errorf("first row = %s %v", rows[0].ID, rows[0].Items)
+	}
+
+	rendered := doc.Render()
+	if strings.Contains(rendered, "Kubernetes") || strings.Contains(rendered, "Cloud &") {
+		t.Errorf("unexpected content in render:\n%s", rendered)
+	}
+	reparsed, err := parseResume(rendered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range reparsed.Bullets() {
+		if !original[b.Text] {
+			t.Errorf("bullet %q not in original", b.Text)
+		}
+	}
+}
+
+func TestVerifyReordered(t *testing.T) {
+	doc, _ := parseResume(sampleResume)
+	roles := doc.Roles()
+	roles[0].Bullets[0], roles[0].Bullets[2] = roles[0].Bullets[2], roles[0].Bullets[0]
+	reordered := postProcessLatex(doc.Render())
+
+	tests := []struct {
+		name     string
+		tailored string
+		wantErr  bool
+	}{
+		{"reordered", reordered, false},
+		{"rewritten bullet", strings.Replace(reordered, "Mentored 4 engineers", "Mentored 12 engineers", 1), true},
+		{"invented bullet", strings.Replace(reordered, `\item Designed the warehouse`, "\\item Owned a $3M cloud budget\n\\item Designed the warehouse", 1), true},
+		{"invented skill", strings.Replace(reordered, "Go, Python", "Go, Rust, Python", 1), true},
+	}
+	for _, tt := range tests {
+		err := verifyReordered(sampleResume, tt.tailored)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestApplyReorderPlanIgnoresText(t *testing.T) {
+	doc, _ := parseResume(sampleResume)
+	var plan reorderPlan
+	json.Unmarshal([]byte(`{
+		"order": {"R1": ["R1.2", "R1.1"]},
+		"bullets": [{"id": "R1.1", "text": "Built a streaming platform processing 5B events/day"}],
+		"skills": [{"id": "S1", "items": ["Go", "Rust"]}]
+	}`), &plan)
+	applyReorderPlan(doc, &plan)
+
+	if err := verifyReordered(sampleResume, doc.Render()); err != nil {
+		t.Errorf("plan with text fields changed content: %v", err)
+	}
+	if got := doc.Render(); strings.Contains(got, "5B events") || strings.Contains(got, "Rust") {
+		t.Errorf("render holds text from the plan:\n%s", got)
+	}
+}
@@ -0,0 +1,705 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ResumeDoc is a LaTeX resume written against resume.cls, parsed into
+// sections, roles, bullets and skill rows. Every node keeps the source lines
+// it came from so Render reproduces untouched content byte-for-byte.
+type ResumeDoc struct {
+	Name     string
+	Address  []string
+	Preamble []string
+	Sections []*ResumeSection
+	Trailer  []string
+
+	origName    string
+	origAddress []string
+}
+
+type ResumeSection struct {
+	Title  string
+	Before []string
+	Begin  string
+	Blocks []*ResumeBlock
+	End    string
+
+	origTitle string
+}
+
+// ResumeBlock is one of: plain lines, a role (rSubsection), a bullet list
+// (itemize) or a skill table (tabular).
+type ResumeBlock struct {
+	Lines  []string
+	Role   *ResumeRole
+	List   *ResumeList
+	Skills *SkillTable
+}
+
+type ResumeRole struct {
+	Employer string
+	Dates    string
+	Title    string
+	Location string
+	ResumeList
+
+	orig [4]string
+}
+
+func (r *ResumeRole) fields() [4]string {
+	return [4]string{r.Employer, r.Dates, r.Title, r.Location}
+}
+
+type ResumeList struct {
+	Begin   string
+	Lead    []string
+	Bullets []*Bullet
+	Tail    []string
+	End     string
+}
+
+type Bullet struct {
+	ID      string
+	Text    string
+	lead    []string
+	raw     []string
+	comment string
+	orig    string
+}
+
+type SkillTable struct {
+	Begin string
+	Lead  []string
+	Rows  []*SkillRow
+	End   string
+}
+
+type SkillRow struct {
+	ID       string
+	Category string
+	Items    []string
+	prefix   string
+	suffix   string
+	raw      string
+	trail    []string
+	orig     []string
+}
+
+var (
+	beginRe = regexp.MustCompile(`^\s*\\begin\{([A-Za-z*]+)\}`)
+	endRe   = regexp.MustCompile(`^\s*\\end\{([A-Za-z*]+)\}`)
+	itemRe  = regexp.MustCompile(`^(\s*)\\item(?:$|[^A-Za-z])`)
+
+	// layoutRe matches a line holding only a spacing or break command, which
+	// separates bullets rather than continuing one.
+	layoutRe = regexp.MustCompile(`^\s*\\(vspace|vskip|hspace|smallskip|medskip|bigskip|newline|linebreak|pagebreak|newpage|clearpage|noindent|par|hfill|vfill|columnbreak)\*?(\[[^\]]*\])?(\{[^{}]*\})?\s*(%.*)?$`)
+)
+
+func parseResume(src string) (*ResumeDoc, error) {
+	p := &resumeParser{lines: strings.Split(src, "\n")}
+	return p.parse()
+}
+
+type resumeParser struct {
+	lines []string
+	pos   int
+	roles int
+	lists int
+	rows  int
+}
+
+func (p *resumeParser) next() (string, bool) {
+	if p.pos >= len(p.lines) {
+		return "", false
+	}
+	line := p.lines[p.pos]
+	p.pos++
+	return line, true
+}
+
+func (p *resumeParser) parse() (*ResumeDoc, error) {
+	doc := &ResumeDoc{}
+	var pending []string
+	inSections := false
+
+	for {
+		line, ok := p.next()
+		if !ok {
+			break
+		}
+		if env := envName(beginRe, line); env == "rSection" {
+			section, err := p.parseSection(line)
+			if err != nil {
+				return nil, err
+			}
+			if !inSections {
+				doc.Preamble = pending
+				inSections = true
+			} else {
+				section.Before = pending
+			}
+			pending = nil
+			doc.Sections = append(doc.Sections, section)
+			continue
+		}
+		if !inSections {
+			if args := commandArgs(line, `\name`); len(args) > 0 {
+				doc.Name = args[0]
+			}
+			if args := commandArgs(line, `\address`); len(args) > 0 {
+				doc.Address = append(doc.Address, args[0])
+			}
+		}
+		pending = append(pending, line)
+	}
+
+	if inSections {
+		doc.Trailer = pending
+	} else {
+		doc.Preamble = pending
+	}
+	doc.origName = doc.Name
+	doc.origAddress = append([]string(nil), doc.Address...)
+	return doc, nil
+}
+
+func (p *resumeParser) parseSection(begin string) (*ResumeSection, error) {
+	s := &ResumeSection{Begin: begin}
+	if args := braceArgs(begin[strings.Index(begin, "{rSection}")+len("{rSection}"):]); len(args) > 0 {
+		s.Title = args[0]
+	}
+	s.origTitle = s.Title
+
+	var text []string
+	flush := func() {
+		if len(text) > 0 {
+			s.Blocks = append(s.Blocks, &ResumeBlock{Lines: text})
+			text = nil
+		}
+	}
+
+	for {
+		line, ok := p.next()
+		if !ok {
+			return nil, fmt.Errorf("section %q: missing \\end{rSection}", s.Title)
+		}
+		if envName(endRe, line) == "rSection" {
+			flush()
+			s.End = line
+			return s, nil
+		}
+
+		switch envName(beginRe, line) {
+		case "rSubsection":
+			flush()
+			role, err := p.parseRole(line)
+			if err != nil {
+				return nil, err
+			}
+			s.Blocks = append(s.Blocks, &ResumeBlock{Role: role})
+		case "itemize", "enumerate":
+			flush()
+			p.lists++
+			list, err := p.parseList(line, fmt.Sprintf("L%d", p.lists))
+			if err != nil {
+				return nil, err
+			}
+			s.Blocks = append(s.Blocks, &ResumeBlock{List: list})
+		case "tabular", "tabular*", "tabularx":
+			flush()
+			table, err := p.parseSkills(line)
+			if err != nil {
+				return nil, err
+			}
+			s.Blocks = append(s.Blocks, &ResumeBlock{Skills: table})
+		default:
+			text = append(text, line)
+		}
+	}
+}
+
+func (p *resumeParser) parseRole(begin string) (*ResumeRole, error) {
+	p.roles++
+	role := &ResumeRole{}
+	args := braceArgs(begin[strings.Index(begin, "{rSubsection}")+len("{rSubsection}"):])
+	for len(args) < 4 {
+		args = append(args, "")
+	}
+	role.Employer, role.Dates, role.Title, role.Location = args[0], args[1], args[2], args[3]
+	role.orig = role.fields()
+
+	list, err := p.parseList(begin, fmt.Sprintf("R%d", p.roles))
+	if err != nil {
+		return nil, fmt.Errorf("role %q: %v", role.Employer, err)
+	}
+	role.ResumeList = *list
+	return role, nil
+}
+
+// parseList reads \item bullets until the environment opened by begin ends.
+// Lines between two bullets that don't continue the first (blank lines,
+// comments, sub-headings, \vspace...) travel with the bullet below them;
+// those after the last bullet go to Tail.
+func (p *resumeParser) parseList(begin, idPrefix string) (*ResumeList, error) {
+	env := envName(beginRe, begin)
+	list := &ResumeList{Begin: begin}
+	var current *Bullet
+	var pending []string
+	depth, itemDepth := 0, 0
+
+	closeBullet := func() {
+		if current == nil {
+			return
+		}
+		current.orig = current.Text
+		list.Bullets = append(list.Bullets, current)
+		current = nil
+	}
+
+	for {
+		line, ok := p.next()
+		if !ok {
+			return nil, fmt.Errorf("missing \\end{%s}", env)
+		}
+		if envName(endRe, line) == env && depth == 0 {
+			closeBullet()
+			if len(list.Bullets) == 0 {
+				list.Lead = append(list.Lead, pending...)
+			} else {
+				list.Tail = append(list.Tail, pending...)
+			}
+			list.End = line
+			return list, nil
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case itemRe.MatchString(line) && depth == itemDepth:
+			closeBullet()
+			text, comment := splitComment(line[len(itemRe.FindStringSubmatch(line)[1])+len(`\item`):])
+			current = &Bullet{
+				ID:      fmt.Sprintf("%s.%d", idPrefix, len(list.Bullets)+1),
+				Text:    strings.TrimSpace(text),
+				lead:    pending,
+				raw:     []string{line},
+				comment: comment,
+			}
+			pending = nil
+		case trimmed == "" || strings.HasPrefix(trimmed, "%"):
+			pending = append(pending, line)
+		case current != nil && len(pending) == 0 && !beginRe.MatchString(line) && !endRe.MatchString(line) && !layoutRe.MatchString(line):
+			text, comment := splitComment(line)
+			if comment != "" {
+				current.comment = strings.TrimSpace(current.comment + " " + comment)
+			}
+			current.raw = append(current.raw, line)
+			current.Text = strings.TrimSpace(current.Text + " " + strings.TrimSpace(text))
+		default:
+			closeBullet()
+			if beginRe.MatchString(line) {
+				depth++
+				if len(list.Bullets) == 0 {
+					itemDepth = depth
+				}
+			} else if endRe.MatchString(line) && depth > 0 {
+				depth--
+			}
+			if len(list.Bullets) == 0 {
+				list.Lead = append(list.Lead, pending...)
+				list.Lead = append(list.Lead, line)
+				pending = nil
+			} else {
+				pending = append(pending, line)
+			}
+		}
+	}
+}
+
+// splitComment separates a trailing LaTeX comment from s.
+func splitComment(s string) (text, comment string) {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == '%' {
+			return s[:i], s[i:]
+		}
+	}
+	return s, ""
+}
+
+func (p *resumeParser) parseSkills(begin string) (*SkillTable, error) {
+	env := envName(beginRe, begin)
+	table := &SkillTable{Begin: begin}
+
+	for {
+		line, ok := p.next()
+		if !ok {
+			return nil, fmt.Errorf("missing \\end{%s}", env)
+		}
+		if envName(endRe, line) == env {
+			table.End = line
+			return table, nil
+		}
+
+		if row := parseSkillRow(line); row != nil {
+			p.rows++
+			row.ID = fmt.Sprintf("S%d", p.rows)
+			table.Rows = append(table.Rows, row)
+			continue
+		}
+		if n := len(table.Rows); n > 0 {
+			table.Rows[n-1].trail = append(table.Rows[n-1].trail, line)
+		} else {
+			table.Lead = append(table.Lead, line)
+		}
+	}
+}
+
+var rowEndRe = regexp.MustCompile(`\s*\\\\.*$`)
+
+func parseSkillRow(line string) *SkillRow {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "%") {
+		return nil
+	}
+	amp := unescapedIndex(line, '&')
+	if amp < 0 {
+		return nil
+	}
+
+	rest := line[amp+1:]
+	sepLen := len(rest) - len(strings.TrimLeft(rest, " \t"))
+	body := rest[sepLen:]
+	suffix := ""
+	if loc := rowEndRe.FindStringIndex(body); loc != nil {
+		suffix = body[loc[0]:]
+		body = body[:loc[0]]
+	}
+
+	row := &SkillRow{
+		Category: strings.TrimSpace(line[:amp]),
+		prefix:   line[:amp+1+sepLen],
+		suffix:   suffix,
+		raw:      line,
+	}
+	for _, item := range splitTopLevel(body, ',') {
+		if item = strings.TrimSpace(item); item != "" {
+			row.Items = append(row.Items, item)
+		}
+	}
+	row.orig = append([]string(nil), row.Items...)
+	return row
+}
+
+// Render writes the document back to LaTeX. Unmodified nodes are emitted
+// from their source lines; edited headers are rebuilt from their fields.
+func (d *ResumeDoc) Render() string {
+	out := d.renderPreamble()
+	for _, s := range d.Sections {
+		out = append(out, s.Before...)
+		begin := s.Begin
+		if s.Title != s.origTitle {
+			begin = setBraceArgs(begin, "{rSection}", []string{s.Title})
+		}
+		out = append(out, begin)
+		for _, b := range s.Blocks {
+			out = append(out, b.render()...)
+		}
+		out = append(out, s.End)
+	}
+	out = append(out, d.Trailer...)
+	return strings.Join(out, "\n")
+}
+
+// renderPreamble rewrites the \name and \address lines when those fields
+// changed. Added addresses follow the last existing one; removed ones drop
+// their line.
+func (d *ResumeDoc) renderPreamble() []string {
+	if d.Name == d.origName && strings.Join(d.Address, "\x00") == strings.Join(d.origAddress, "\x00") {
+		return append([]string(nil), d.Preamble...)
+	}
+
+	var out []string
+	anchor, addresses := -1, 0
+	for _, line := range d.Preamble {
+		switch {
+		case len(commandArgs(line, `\name`)) > 0:
+			if d.Name != d.origName {
+				line = setBraceArgs(line, `\name`, []string{d.Name})
+			}
+		case len(commandArgs(line, `\address`)) > 0:
+			if addresses >= len(d.Address) {
+				addresses++
+				continue
+			}
+			line = setBraceArgs(line, `\address`, []string{d.Address[addresses]})
+			addresses++
+		case anchor < 0 && envName(beginRe, line) == "document":
+			anchor = len(out)
+			out = append(out, line)
+			continue
+		default:
+			out = append(out, line)
+			continue
+		}
+		out = append(out, line)
+		anchor = len(out)
+	}
+	if anchor < 0 {
+		anchor = len(out)
+	}
+
+	var added []string
+	if d.origName == "" && d.Name != "" {
+		added = append(added, `\name{`+d.Name+`}`)
+	}
+	for i := addresses; i < len(d.Address); i++ {
+		added = append(added, `\address{`+d.Address[i]+`}`)
+	}
+	return append(out[:anchor:anchor], append(added, out[anchor:]...)...)
+}
+
+func (b *ResumeBlock) render() []string {
+	switch {
+	case b.Role != nil:
+		lines := b.Role.ResumeList.render()
+		if fields := b.Role.fields(); fields != b.Role.orig {
+			lines[0] = setBraceArgs(lines[0], "{rSubsection}", fields[:])
+		}
+		return lines
+	case b.List != nil:
+		return b.List.render()
+	case b.Skills != nil:
+		return b.Skills.render()
+	}
+	return b.Lines
+}
+
+func (l *ResumeList) render() []string {
+	out := append([]string{l.Begin}, l.Lead...)
+	for _, b := range l.Bullets {
+		out = append(out, b.render()...)
+	}
+	out = append(out, l.Tail...)
+	return append(out, l.End)
+}
+
+func (b *Bullet) render() []string {
+	out := append([]string(nil), b.lead...)
+	if b.Text == b.orig {
+		return append(out, b.raw...)
+	}
+	line := itemRe.FindStringSubmatch(b.raw[0])[1] + `\item ` + b.Text
+	if b.comment != "" {
+		line += " " + b.comment
+	}
+	return append(out, line)
+}
+
+func (t *SkillTable) render() []string {
+	out := append([]string{t.Begin}, t.Lead...)
+	for _, r := range t.Rows {
+		out = append(out, r.render()...)
+	}
+	return append(out, t.End)
+}
+
+func (r *SkillRow) render() []string {
+	if len(r.Items) == 0 {
+		return r.trail
+	}
+	line := r.raw
+	if strings.Join(r.Items, "\x00") != strings.Join(r.orig, "\x00") {
+		line = r.prefix + strings.Join(r.Items, ", ") + r.suffix
+	}
+	return append([]string{line}, r.trail...)
+}
+
+// Roles returns every rSubsection in document order.
+func (d *ResumeDoc) Roles() []*ResumeRole {
+	var roles []*ResumeRole
+	for _, s := range d.Sections {
+		for _, b := range s.Blocks {
+			if b.Role != nil {
+				roles = append(roles, b.Role)
+			}
+		}
+	}
+	return roles
+}
+
+// Lists returns every bullet list, roles included, in document order.
+func (d *ResumeDoc) Lists() []*ResumeList {
+	var lists []*ResumeList
+	for _, s := range d.Sections {
+		for _, b := range s.Blocks {
+			if b.Role != nil {
+				lists = append(lists, &b.Role.ResumeList)
+			} else if b.List != nil {
+				lists = append(lists, b.List)
+			}
+		}
+	}
+	return lists
+}
+
+func (d *ResumeDoc) Bullets() []*Bullet {
+	var bullets []*Bullet
+	for _, l := range d.Lists() {
+		bullets = append(bullets, l.Bullets...)
+	}
+	return bullets
+}
+
+func (d *ResumeDoc) SkillRows() []*SkillRow {
+	var rows []*SkillRow
+	for _, s := range d.Sections {
+		for _, b := range s.Blocks {
+			if b.Skills != nil {
+				rows = append(rows, b.Skills.Rows...)
+			}
+		}
+	}
+	return rows
+}
+
+func envName(re *regexp.Regexp, line string) string {
+	if m := re.FindStringSubmatch(line); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+// commandArgs returns the brace arguments of cmd if line starts with it.
+func commandArgs(line, cmd string) []string {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, cmd) {
+		return nil
+	}
+	rest := trimmed[len(cmd):]
+	if rest == "" || (rest[0] != '{' && rest[0] != ' ') {
+		return nil
+	}
+	return braceArgs(rest)
+}
+
+// braceArgs parses consecutive {...} groups at the start of s.
+func braceArgs(s string) []string {
+	var args []string
+	for _, span := range braceArgSpans(s) {
+		args = append(args, s[span[0]:span[1]])
+	}
+	return args
+}
+
+// braceArgSpans returns the byte ranges of the contents of consecutive
+// {...} groups at the start of s.
+func braceArgSpans(s string) [][2]int {
+	var spans [][2]int
+	i := 0
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == '\t') {
+			i++
+		}
+		if i >= len(s) || s[i] != '{' {
+			return spans
+		}
+		depth, start := 0, i+1
+		for ; i < len(s); i++ {
+			if s[i] == '\\' {
+				i++
+				continue
+			}
+			if s[i] == '{' {
+				depth++
+			} else if s[i] == '}' {
+				depth--
+				if depth == 0 {
+					break
+				}
+			}
+		}
+		if i >= len(s) {
+			return spans
+		}
+		spans = append(spans, [2]int{start, i})
+		i++
+	}
+}
+
+// setBraceArgs replaces the brace arguments following marker in line with
+// args, adding groups the line lacks and keeping whatever follows them.
+func setBraceArgs(line, marker string, args []string) string {
+	at := strings.Index(line, marker)
+	if at < 0 {
+		return line
+	}
+	at += len(marker)
+	rest := line[at:]
+	spans := braceArgSpans(rest)
+
+	var b strings.Builder
+	b.WriteString(line[:at])
+	prev := 0
+	for i, span := range spans {
+		if i >= len(args) {
+			break
+		}
+		b.WriteString(rest[prev:span[0]])
+		b.WriteString(args[i])
+		prev = span[1]
+	}
+	end := 0
+	if len(spans) > 0 {
+		end = spans[len(spans)-1][1] + 1
+	}
+	b.WriteString(rest[prev:end])
+	for i := len(spans); i < len(args); i++ {
+		b.WriteString("{" + args[i] + "}")
+	}
+	b.WriteString(rest[end:])
+	return b.String()
+}
+
+func unescapedIndex(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == '%' {
+			return -1
+		}
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// splitTopLevel splits s on sep outside of braces.
+func splitTopLevel(s string, sep byte) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case sep:
+			if depth == 0 {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
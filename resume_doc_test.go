@@ -0,0 +1,242 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleResume = `\documentclass{resume}
+\usepackage[left=0.75in,top=0.6in,right=0.75in,bottom=0.6in]{geometry}
+
+\name{Jane Doe}
+\address{San Francisco, CA \\ jane@example.com}
+
+\begin{document}
+
+%----------------------------------------------------------------------------------------
+\begin{rSection}{Experience}
+
+\begin{rSubsection}{Acme Corp}{Jan 2021 -- Present}{Staff Data Engineer}{Remote}
+  \item Built a streaming platform on Kafka and Flink processing 2B events/day
+  \item Led migration from Airflow 1 to Airflow 2
+    across 300 DAGs % multi-line bullet
+  % resumectl:pin-top
+  \item Mentored 4 engineers
+\end{rSubsection}
+
+\begin{rSubsection}{Initech}{2017 -- 2020}{Data Engineer}{Austin, TX}
+\begin{itemize}
+\item Designed the warehouse schema in Snowflake
+\item Cut nightly batch runtime by 60\%
+\end{itemize}
+\end{rSubsection}
+
+\end{rSection}
+
+\begin{rSection}{Technical Strengths}
+\begin{tabular}{ @{} >{\bfseries}l @{\hspace{6ex}} l }
+Languages & Go, Python, SQL, Scala \\
+Data & Kafka, Flink, Spark, {dbt, Airflow} \\
+% tooling
+Cloud &  AWS, GCP\\
+\end{tabular}
+\end{rSection}
+
+\begin{rSection}{Projects}
+\begin{itemize}
+  \item resumectl: self-custodial job hunting CLI
+\end{itemize}
+\end{rSection}
+
+\begin{rSection}{Education}
+{\bf State University} \hfill {\em 2017} \\
+B.S. Computer Science
+\end{rSection}
+
+\end{document}
+`
+
+func TestParseResumeRoundTrip(t *testing.T) {
+	for _, src := range []string{sampleResume, strings.ReplaceAll(sampleResume, "\n", "\r\n"), strings.TrimSuffix(sampleResume, "\n")} {
+		doc, err := parseResume(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := doc.Render(); got != src {
+			t.Errorf("round trip differs:\n%s", got)
+		}
+	}
+}
+
+func TestParseResumeModel(t *testing.T) {
+	doc, err := parseResume(sampleResume)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.Name != "Jane Doe" || len(doc.Address) != 1 {
+		t.Errorf("name/address = %q %q", doc.Name, doc.Address)
+	}
+	if len(doc.Sections) != 4 || doc.Sections[1].Title != "Technical Strengths" {
+		t.Fatalf("sections = %d", len(doc.Sections))
+	}
+
+	roles := doc.Roles()
+	if len(roles) != 2 {
+		t.Fatalf("roles = %d", len(roles))
+	}
+	r := roles[0]
+	if r.Employer != "Acme Corp" || r.Dates != "Jan 2021 -- Present" || r.Title != "Staff Data Engineer" || r.Location != "Remote" {
+		t.Errorf("role = %+v", r)
+	}
+	if len(r.Bullets) != 3 || r.Bullets[1].ID != "R1.2" || r.Bullets[1].Text != "Led migration from Airflow 1 to Airflow 2 across 300 DAGs" {
+		t.Errorf("bullets = %+v", r.Bullets)
+	}
+	if len(roles[1].Bullets) != 2 || roles[1].Bullets[1].Text != `Cut nightly batch runtime by 60\%` {
+		t.Errorf("nested itemize bullets = %+v", roles[1].Bullets)
+	}
+
+	rows := doc.SkillRows()
+	if len(rows) != 3 || rows[1].ID != "S2" || rows[1].Category != "Data" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	if got := strings.Join(rows[1].Items, "|"); got != "Kafka|Flink|Spark|{dbt, Airflow}" {
+		t.Errorf("items = %q", got)
+	}
+
+	if len(doc.Bullets()) != 6 {
+		t.Errorf("all bullets = %d, want 6", len(doc.Bullets()))
+	}
+}
+
+func TestRenderEdits(t *testing.T) {
+	doc, _ := parseResume(sampleResume)
+	acme := doc.Roles()[0]
+	acme.Bullets[0], acme.Bullets[2] = acme.Bullets[2], acme.Bullets[0]
+	acme.Bullets[1].Text = "Led Airflow 2 migration across 300 DAGs"
+	rows := doc.SkillRows()
+	rows[0].Items = []string{"Python", "Go"}
+	rows[2].Items = nil
+
+	got := doc.Render()
+	for _, want := range []string{
+		"  % resumectl:pin-top\n  \\item Mentored 4 engineers\n  \\item Led Airflow 2 migration across 300 DAGs % multi-line bullet\n  \\item Built a streaming",
+		"Languages & Python, Go \\\\\n",
+		"Data & Kafka, Flink, Spark, {dbt, Airflow} \\\\\n% tooling\n\\end{tabular}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("render missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestRenderHeaderEdits(t *testing.T) {
+	doc, _ := parseResume(sampleResume)
+	doc.Name = "Jane Q. Doe"
+	doc.Address = []string{"Remote", "jane@example.com"}
+	doc.Sections[0].Title = "Work Experience"
+	initech := doc.Roles()[1]
+	initech.Employer = "Initech, Inc."
+	initech.Location = "Remote"
+
+	got := doc.Render()
+	for _, want := range []string{
+		"\\name{Jane Q. Doe}\n\\address{Remote}\n\\address{jane@example.com}\n\n\\begin{document}",
+		"\\begin{rSection}{Work Experience}\n",
+		"\\begin{rSubsection}{Initech, Inc.}{2017 -- 2020}{Data Engineer}{Remote}\n\\begin{itemize}",
+		"\\begin{rSubsection}{Acme Corp}{Jan 2021 -- Present}{Staff Data Engineer}{Remote}\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("render missing %q:\n%s", want, got)
+		}
+	}
+
+	reparsed, err := parseResume(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := reparsed.Roles()[1]; r.Employer != "Initech, Inc." || r.Location != "Remote" || reparsed.Sections[0].Title != "Work Experience" {
+		t.Errorf("reparsed headers = %+v, %q", r.fields(), reparsed.Sections[0].Title)
+	}
+
+	doc.Address = nil
+	if got := doc.Render(); strings.Contains(got, "\\address") {
+		t.Errorf("removed address still rendered:\n%s", got)
+	}
+}
+
+func TestSetBraceArgs(t *testing.T) {
+	tests := []struct {
+		line, marker string
+		args         []string
+		want         string
+	}{
+		{`\begin{rSection}{Skills} % main`, "{rSection}", []string{"Tools"}, `\begin{rSection}{Tools} % main`},
+		{`\begin{rSubsection}{A}{2020}{Eng}{}`, "{rSubsection}", []string{"B", "2020", "Eng", "NYC"}, `\begin{rSubsection}{B}{2020}{Eng}{NYC}`},
+		{`\begin{rSubsection}{A}{2020}`, "{rSubsection}", []string{"A", "2020", "Eng", "NYC"}, `\begin{rSubsection}{A}{2020}{Eng}{NYC}`},
+		{`\name{Jane {\bf Doe}}`, `\name`, []string{"Jane Doe"}, `\name{Jane Doe}`},
+	}
+	for _, tt := range tests {
+		if got := setBraceArgs(tt.line, tt.marker, tt.args); got != tt.want {
+			t.Errorf("setBraceArgs(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestParseListSeparators(t *testing.T) {
+	tests := []struct {
+		name  string
+		list  string
+		texts []string
+		lead  string // lead of the last bullet
+		tail  string
+	}{
+		{
+			name:  "sub-heading between bullets",
+			list:  "\\item One\n\n\\textbf{Sub}\n\\item Two\n\\item Three\n",
+			texts: []string{"One", "Two", "Three"},
+		},
+		{
+			name:  "vspace after a bullet",
+			list:  "\\item One\n\\vspace{2pt}\n\\item Two\n  continued\n\\vspace{-4pt} % tighten\n",
+			texts: []string{"One", "Two continued"},
+			tail:  "\\vspace{-4pt} % tighten",
+		},
+		{
+			name:  "vspace before the last bullet",
+			list:  "\\item One\n\\vspace{2pt}\n\\item Two\n",
+			texts: []string{"One", "Two"},
+			lead:  "\\vspace{2pt}",
+		},
+	}
+	for _, tt := range tests {
+		src := "\\begin{rSection}{Projects}\n\\begin{itemize}\n" + tt.list + "\\end{itemize}\n\\end{rSection}"
+		doc, err := parseResume(src)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := doc.Render(); got != src {
+			t.Errorf("%s: round trip differs:\n%s", tt.name, got)
+		}
+		lists := doc.Lists()
+		if len(lists) != 1 {
+			t.Fatalf("%s: got %d lists", tt.name, len(lists))
+		}
+		list := lists[0]
+		var texts []string
+		for _, b := range list.Bullets {
+			texts = append(texts, b.Text)
+		}
+		if strings.Join(texts, "|") != strings.Join(tt.texts, "|") {
+			t.Errorf("%s: bullets = %q, want %q", tt.name, texts, tt.texts)
+		}
+		if tt.lead != "" {
+			if last := list.Bullets[len(list.Bullets)-1]; strings.Join(last.lead, "\n") != tt.lead {
+				t.Errorf("%s: last bullet lead = %q", tt.name, last.lead)
+			}
+		}
+		if got := strings.Join(list.Tail, "\n"); got != tt.tail {
+			t.Errorf("%s: tail = %q, want %q", tt.name, got, tt.tail)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var matchReview bool
+
+// reviewDecision records what the user did with one tailoring change.
+type reviewDecision struct {
+	Kind     string `json:"kind"`
+	Where    string `json:"where"`
+	Old      string `json:"old,omitempty"`
+	New      string `json:"new,omitempty"`
+	Decision string `json:"decision"` // accept, reject, edit
+	Edited   string `json:"edited,omitempty"`
+}
+
+// reviewMatchResult takes the user through the changes tailoring made to
+// resume and replaces result's LaTeX with the accepted set.
+func reviewMatchResult(resume string, result *MatchResult, in *bufio.Reader, out io.Writer) error {
+	changes, err := diffResumes(resume, result.TailoredLatex)
+	if err != nil {
+		return err
+	}
+	result.Review = []reviewDecision{}
+	if len(changes) == 0 {
+		fmt.Fprintf(out, "\n%s Nothing to review\n", color.GreenString("✓"))
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n%s\n", color.New(color.Bold).Sprintf("Review %d changes", len(changes)))
+	decisions := reviewChanges(in, out, changes)
+	latex, notes, err := applyReview(resume, result.TailoredLatex, decisions)
+	if err != nil {
+		return err
+	}
+	for _, n := range notes {
+		fmt.Fprintf(out, "%s %s\n", color.YellowString("⚠"), n)
+	}
+	latex, violations := enforceLocks(resume, latex)
+	result.LockViolations = append(result.LockViolations, violations...)
+	printLockViolations(out, violations)
+
+	result.TailoredLatex, result.Review = latex, decisions
+	result.Drift = detectDrift(resume, latex)
+	fmt.Fprintf(out, "\n%s %s\n", color.GreenString("✓"), reviewSummary(decisions))
+	if len(result.Drift) > 0 {
+		fmt.Fprintf(out, "%s %d drift findings remain\n", color.YellowString("⚠"), len(result.Drift))
+	}
+	return nil
+}
+
+func reviewSummary(decisions []reviewDecision) string {
+	counts := make(map[string]int)
+	for _, d := range decisions {
+		counts[d.Decision]++
+	}
+	return fmt.Sprintf("Reviewed %d changes: %d accepted, %d rejected, %d edited", len(decisions), counts["accept"], counts["reject"], counts["edit"])
+}
+
+// reviewChanges walks through changes one at a time and asks whether to
+// accept, reject or edit each. Upper-case A or R settles the rest; end of
+// input accepts whatever is left. reader is shared with the prompts that
+// follow, so it must be the caller's one reader over stdin.
+func reviewChanges(reader *bufio.Reader, out io.Writer, changes []resumeChange) []reviewDecision {
+	decisions := make([]reviewDecision, len(changes))
+	rest := ""
+	for i, c := range changes {
+		decisions[i] = reviewDecision{Kind: c.Kind, Where: c.Where, Old: c.Old, New: c.New, Decision: "accept"}
+		if rest != "" {
+			decisions[i].Decision = rest
+			continue
+		}
+
+		fmt.Fprintf(out, "\n[%d/%d] %s\n  %s\n", i+1, len(changes), color.New(color.Bold).Sprint(c.Where), c.describe())
+		for {
+			fmt.Fprintf(out, "  [a]ccept, [r]eject, [e]dit, [A]ccept rest, [R]eject rest: ")
+			line, err := reader.ReadString('\n')
+			answer := strings.TrimSpace(line)
+			if err != nil && answer == "" {
+				rest = "accept"
+				break
+			}
+			switch answer {
+			case "", "a", "y":
+			case "r", "n":
+				decisions[i].Decision = "reject"
+			case "A":
+				rest = "accept"
+			case "R":
+				decisions[i].Decision, rest = "reject", "reject"
+			case "e":
+				if !editable(c) {
+					fmt.Fprintf(out, "  %s can't be edited, accept or reject it\n", c.Kind)
+					continue
+				}
+				fmt.Fprintf(out, "  LaTeX: %s\n  New text: ", editSeed(c))
+				text, _ := reader.ReadString('\n')
+				if text = strings.TrimSpace(text); text == "" {
+					continue
+				}
+				decisions[i].Decision, decisions[i].Edited = "edit", text
+			default:
+				continue
+			}
+			break
+		}
+	}
+	return decisions
+}
+
+func editable(c resumeChange) bool {
+	switch c.Kind {
+	case "edited", "added", "removed", "header":
+		return true
+	}
+	return false
+}
+
+// editSeed is the LaTeX the user starts an edit from.
+func editSeed(c resumeChange) string {
+	switch {
+	case c.Kind == "header":
+		return roleField(c.tailoredRole, c.Detail)
+	case c.tailored != nil:
+		return c.tailored.Text
+	case c.orig != nil:
+		return c.orig.Text
+	}
+	return c.New
+}
+
+// applyReview rebuilds the tailored resume from the accepted changes:
+// rejected edits and header changes are reverted, rejected additions
+// dropped, rejected removals put back and rejected moves undone. decisions
+// are parallel to diffResumes(original, tailored). It returns the new
+// LaTeX and a note for each change that could not be applied.
+func applyReview(original, tailored string, decisions []reviewDecision) (string, []string, error) {
+	origDoc, err := parseResume(original)
+	if err != nil {
+		return "", nil, fmt.Errorf("parsing original: %v", err)
+	}
+	newDoc, err := parseResume(tailored)
+	if err != nil {
+		return "", nil, fmt.Errorf("parsing tailored resume: %v", err)
+	}
+	changes := diffDocs(origDoc, newDoc)
+	if len(changes) != len(decisions) {
+		return "", nil, fmt.Errorf("%d decisions for %d changes", len(decisions), len(changes))
+	}
+
+	// Where each bullet sits in the original, for putting bullets back in
+	// their original order.
+	origIndex := make(map[*Bullet]int)
+	for _, p := range pairLists(origDoc, newDoc) {
+		if p.orig == nil {
+			continue
+		}
+		for i, o := range p.orig.Bullets {
+			origIndex[o] = i
+		}
+		if p.tailored != nil {
+			for i, o := range matchBullets(p.orig.Bullets, p.tailored.Bullets) {
+				if o != nil {
+					origIndex[p.tailored.Bullets[i]] = origIndex[o]
+				}
+			}
+		}
+	}
+
+	var notes []string
+	// Text first, then drops, then re-inserts, so positions settle last.
+	for pass := 0; pass < 3; pass++ {
+		for i, c := range changes {
+			d := decisions[i]
+			if d.Decision == "accept" {
+				continue
+			}
+			switch {
+			case pass == 0 && c.Kind == "header":
+				text := roleField(c.origRole, c.Detail)
+				if d.Decision == "edit" {
+					text = d.Edited
+				}
+				setRoleField(c.tailoredRole, c.Detail, text)
+			case pass == 0 && (c.Kind == "edited" || c.Kind == "added") && d.Decision == "edit":
+				c.tailored.Text = d.Edited
+			case pass == 0 && c.Kind == "edited":
+				c.tailored.Text = c.orig.Text
+			case pass == 1 && c.Kind == "added" && d.Decision == "reject":
+				c.tailoredList.Bullets = removeBullet(c.tailoredList.Bullets, c.tailored)
+			case pass == 1 && c.Kind == "skill_added":
+				c.row.Items = removeItem(c.row.Items, c.item)
+			case pass == 2 && c.Kind == "removed":
+				if c.tailoredList == nil {
+					notes = append(notes, fmt.Sprintf("could not restore %q: %s is gone from the tailored resume", c.Old, c.Where))
+					continue
+				}
+				b := c.orig
+				if d.Decision == "edit" {
+					b.Text = d.Edited
+				}
+				c.tailoredList.Bullets = insertInOrder(c.tailoredList.Bullets, b, origIndex)
+			case pass == 2 && c.Kind == "moved":
+				list := removeBullet(c.tailoredList.Bullets, c.tailored)
+				c.tailoredList.Bullets = insertInOrder(list, c.tailored, origIndex)
+			case pass == 2 && c.Kind == "skill_removed":
+				row := findSkillRow(newDoc, c.row.Category)
+				if row == nil {
+					notes = append(notes, fmt.Sprintf("could not restore skill %q: row %s is gone from the tailored resume", c.Old, c.Where))
+					continue
+				}
+				at := min(c.OldPos-1, len(row.Items))
+				row.Items = append(row.Items[:at:at], append([]string{c.item}, row.Items[at:]...)...)
+			}
+		}
+	}
+	return newDoc.Render(), notes, nil
+}
+
+func removeBullet(list []*Bullet, b *Bullet) []*Bullet {
+	out := make([]*Bullet, 0, len(list))
+	for _, x := range list {
+		if x != b {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func removeItem(items []string, item string) []string {
+	for i, x := range items {
+		if x == item {
+			return append(items[:i:i], items[i+1:]...)
+		}
+	}
+	return items
+}
+
+// insertInOrder puts b before the first bullet that comes after it in the
+// original. Bullets with no original are skipped over.
+func insertInOrder(list []*Bullet, b *Bullet, origIndex map[*Bullet]int) []*Bullet {
+	at := len(list)
+	for i, x := range list {
+		if idx, ok := origIndex[x]; ok && idx > origIndex[b] {
+			at = i
+			break
+		}
+	}
+	return append(list[:at:at], append([]*Bullet{b}, list[at:]...)...)
+}
+
+func findSkillRow(doc *ResumeDoc, category string) *SkillRow {
+	for _, r := range doc.SkillRows() {
+		if strings.EqualFold(latexPlain(r.Category), latexPlain(category)) {
+			return r
+		}
+	}
+	return nil
+}
+
+func roleField(r *ResumeRole, field string) string {
+	switch field {
+	case "employer":
+		return r.Employer
+	case "dates":
+		return r.Dates
+	case "title":
+		return r.Title
+	}
+	return r.Location
+}
+
+// setRoleField changes one header field; Render rebuilds the role's
+// \begin{rSubsection} line from the fields.
+func setRoleField(r *ResumeRole, field, value string) {
+	switch field {
+	case "employer":
+		r.Employer = value
+	case "dates":
+		r.Dates = value
+	case "title":
+		r.Title = value
+	default:
+		r.Location = value
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"testing"
+)
+
+// reviewTailored moves the pinned bullet up and edits it, drops a bullet,
+// adds one, swaps a skill and changes a title.
+var reviewTailored = strings.NewReplacer(
+	"  \\item Built a streaming platform on Kafka and Flink processing 2B events/day\n  \\item Led migration from Airflow 1 to Airflow 2\n    across 300 DAGs % multi-line bullet\n  % resumectl:pin-top\n  \\item Mentored 4 engineers\n",
+	"  % resumectl:pin-top\n  \\item Mentored 4 data engineers\n  \\item Built a streaming platform on Kafka and Flink processing 2B events/day\n",
+	"\\item Cut nightly batch runtime by 60\\%\n", "\\item Cut nightly batch runtime by 60\\%\n\\item Wrote the on-call runbook\n",
+	"Kafka, Flink, Spark, {dbt, Airflow}", "Kafka, Flink, {dbt, Airflow}, Terraform",
+	"{Data Engineer}{Austin, TX}", "{Senior Data Engineer}{Austin, TX}",
+).Replace(sampleResume)
+
+func TestApplyReview(t *testing.T) {
+	changes, err := diffResumes(sampleResume, reviewTailored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decide := func(d map[string]string) []reviewDecision {
+		out := make([]reviewDecision, len(changes))
+		for i, c := range changes {
+			out[i] = reviewDecision{Kind: c.Kind, Decision: "accept"}
+			if v, ok := d[c.Kind]; ok {
+				out[i].Decision = v
+				if strings.HasPrefix(v, "edit:") {
+					out[i].Decision, out[i].Edited = "edit", strings.TrimPrefix(v, "edit:")
+				}
+			}
+		}
+		return out
+	}
+
+	t.Run("accept all", func(t *testing.T) {
+		got, notes, err := applyReview(sampleResume, reviewTailored, decide(nil))
+		if err != nil || len(notes) > 0 {
+			t.Fatal(err, notes)
+		}
+		if got != reviewTailored {
+			t.Errorf("accepting everything changed the resume:\n%s", got)
+		}
+	})
+
+	t.Run("reject all", func(t *testing.T) {
+		all := map[string]string{}
+		for _, c := range changes {
+			all[c.Kind] = "reject"
+		}
+		got, notes, err := applyReview(sampleResume, reviewTailored, decide(all))
+		if err != nil || len(notes) > 0 {
+			t.Fatal(err, notes)
+		}
+		if changes, _ := diffResumes(sampleResume, got); len(changes) > 0 {
+			t.Errorf("rejecting everything left %d changes:\n%s", len(changes), got)
+		}
+	})
+
+	t.Run("mixed", func(t *testing.T) {
+		got, _, err := applyReview(sampleResume, reviewTailored, decide(map[string]string{
+			"moved":         "reject",
+			"removed":       "edit:Led the Airflow 2 migration",
+			"added":         "reject",
+			"skill_removed": "reject",
+			"header":        "edit:Senior Engineer",
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		doc, err := parseResume(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var acme []string
+		for _, b := range doc.Roles()[0].Bullets {
+			acme = append(acme, b.Text)
+		}
+		want := "Built a streaming platform on Kafka and Flink processing 2B events/day|Led the Airflow 2 migration|Mentored 4 data engineers"
+		if strings.Join(acme, "|") != want {
+			t.Errorf("Acme bullets = %q", acme)
+		}
+		if n := len(doc.Roles()[1].Bullets); n != 2 {
+			t.Errorf("Initech has %d bullets, want 2", n)
+		}
+		if doc.Roles()[1].Title != "Senior Engineer" || !strings.Contains(got, "{Initech}{2017 -- 2020}{Senior Engineer}{Austin, TX}") {
+			t.Errorf("title = %q", doc.Roles()[1].Title)
+		}
+		if !strings.Contains(got, "Kafka, Flink, Spark, {dbt, Airflow}, Terraform") {
+			t.Errorf("skills row:\n%s", got)
+		}
+	})
+}
+
+func TestReviewChanges(t *testing.T) {
+	changes, err := diffResumes(sampleResume, reviewTailored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// header: edit, moved: e (not editable) then r, edited: a, then reject the rest.
+	in := "e\nSenior Engineer\ne\nr\na\nR\n"
+	decisions := reviewChanges(bufio.NewReader(strings.NewReader(in)), io.Discard, changes)
+
+	var got []string
+	for _, d := range decisions {
+		got = append(got, d.Kind+"="+d.Decision+d.Edited)
+	}
+	want := "header=editSenior Engineer,moved=reject,edited=accept,removed=reject,added=reject,skill_removed=reject,skill_added=reject"
+	if strings.Join(got, ",") != want {
+		t.Errorf("got %s", strings.Join(got, ","))
+	}
+
+	decisions = reviewChanges(bufio.NewReader(strings.NewReader("r\n")), io.Discard, changes)
+	if decisions[0].Decision != "reject" || decisions[1].Decision != "accept" {
+		t.Errorf("end of input should accept the rest: %+v", decisions[:2])
+	}
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type SalaryRange struct {
+	Min      float64 `json:"min,omitempty"`
+	Max      float64 `json:"max,omitempty"`
+	Currency string  `json:"currency,omitempty"`
+	Period   string  `json:"period,omitempty"`
+}
+
+var hoursPerPeriod = map[string]float64{
+	"hour":  1,
+	"day":   8,
+	"week":  40,
+	"month": 2080.0 / 12,
+	"year":  2080,
+}
+
+var currencySymbols = []struct {
+	token string
+	code  string
+}{
+	{"us$", "USD"}, {"ca$", "CAD"}, {"c$", "CAD"}, {"a$", "AUD"}, {"au$", "AUD"},
+	{"$", "USD"}, {"€", "EUR"}, {"£", "GBP"}, {"¥", "JPY"}, {"₹", "INR"},
+}
+
+var currencyCodes = regexp.MustCompile(`\b(USD|EUR|GBP|CAD|AUD|CHF|SGD|JPY|INR|SEK|NOK|DKK|PLN)\b`)
+
+var salaryPeriods = []struct {
+	re     *regexp.Regexp
+	period string
+}{
+	{regexp.MustCompile(`(?i)(an|per|/|a)\s*(hour|hr)\b|hourly|/h\b`), "hour"},
+	{regexp.MustCompile(`(?i)(a|per|/)\s*day\b|daily`), "day"},
+	{regexp.MustCompile(`(?i)(a|per|/)\s*(week|wk)\b|weekly`), "week"},
+	{regexp.MustCompile(`(?i)(a|per|/)\s*(month|mo)\b|monthly`), "month"},
+	{regexp.MustCompile(`(?i)(a|per|/)\s*(year|yr|annum)\b|annual|yearly|\bp\.?a\.?\b`), "year"},
+}
+
+var (
+	salaryNumber    = regexp.MustCompile(`(\d+(?:[.,]\d+)*)\s*(?:([kKmM])\b)?`)
+	salarySeparator = regexp.MustCompile(`[.,]`)
+)
+
+func parseSalary(text string) (SalaryRange, bool) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return SalaryRange{}, false
+	}
+
+	var s SalaryRange
+	lower := strings.ToLower(text)
+	for _, c := range currencySymbols {
+		if strings.Contains(lower, c.token) {
+			s.Currency = c.code
+			break
+		}
+	}
+	if m := currencyCodes.FindString(strings.ToUpper(text)); m != "" {
+		s.Currency = m
+	}
+
+	matches := salaryNumber.FindAllStringSubmatch(text, -1)
+	var values []float64
+	var suffixes []string
+	for _, m := range matches {
+		v, ok := parseSalaryNumber(m[1])
+		if !ok {
+			continue
+		}
+		values = append(values, v)
+		suffixes = append(suffixes, strings.ToLower(m[2]))
+		if len(values) == 2 {
+			break
+		}
+	}
+	if len(values) == 0 {
+		return SalaryRange{}, false
+	}
+
+	// "$150-190k" carries the multiplier on the second number only.
+	for i := range values {
+		suffix := suffixes[i]
+		if suffix == "" && len(values) == 2 {
+			other := suffixes[1-i]
+			if other != "" && values[i] < 1000 {
+				suffix = other
+			}
+		}
+		switch suffix {
+		case "k":
+			values[i] *= 1000
+		case "m":
+			values[i] *= 1000000
+		}
+	}
+
+	s.Min = values[0]
+	s.Max = values[0]
+	if len(values) == 2 {
+		s.Min = math.Min(values[0], values[1])
+		s.Max = math.Max(values[0], values[1])
+	}
+
+	for _, p := range salaryPeriods {
+		if p.re.MatchString(text) {
+			s.Period = p.period
+			break
+		}
+	}
+	if s.Period == "" {
+		switch {
+		case s.Max < 500:
+			s.Period = "hour"
+		case s.Max < 20000:
+			s.Period = "month"
+		default:
+			s.Period = "year"
+		}
+	}
+	if s.Currency == "" {
+		s.Currency = "USD"
+	}
+
+	return s, true
+}
+
+// parseAnnualSalary parses a salary given on the command line, where an
+// amount without a period is annual: "150k" and "150000" are both per year.
+func parseAnnualSalary(text string) (SalaryRange, bool) {
+	s, ok := parseSalary(text)
+	if !ok {
+		return s, false
+	}
+	s.Period = "year"
+	for _, p := range salaryPeriods {
+		if p.re.MatchString(text) {
+			s.Period = p.period
+			break
+		}
+	}
+	return s, true
+}
+
+func parseSalaryNumber(raw string) (float64, bool) {
+	// Separators followed by exactly three digits are thousands separators
+	// ("120,000", "80.000"); anything else is a decimal point ("45.50").
+	parts := salarySeparator.Split(raw, -1)
+	var digits strings.Builder
+	digits.WriteString(parts[0])
+	for i, p := range parts[1:] {
+		if len(p) == 3 {
+			digits.WriteString(p)
+		} else if i == len(parts)-2 {
+			digits.WriteString("." + p)
+		} else {
+			return 0, false
+		}
+	}
+	v, err := strconv.ParseFloat(digits.String(), 64)
+	if err != nil || v == 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+func (s SalaryRange) IsZero() bool {
+	return s.Min == 0 && s.Max == 0
+}
+
+func (s SalaryRange) Annual() (float64, float64) {
+	hours, ok := hoursPerPeriod[s.Period]
+	if !ok {
+		return s.Min, s.Max
+	}
+	factor := hoursPerPeriod["year"] / hours
+	return s.Min * factor, s.Max * factor
+}
+
+func (s SalaryRange) String() string {
+	if s.IsZero() {
+		return ""
+	}
+	symbol := s.Currency + " "
+	switch s.Currency {
+	case "USD":
+		symbol = "$"
+	case "EUR":
+		symbol = "€"
+	case "GBP":
+		symbol = "£"
+	}
+
+	format := func(v float64) string {
+		if v >= 1000 {
+			return fmt.Sprintf("%s%gk", symbol, math.Round(v/100)/10)
+		}
+		return fmt.Sprintf("%s%g", symbol, math.Round(v*100)/100)
+	}
+
+	out := format(s.Min)
+	if s.Max != s.Min {
+		out += "–" + format(s.Max)
+	}
+	switch s.Period {
+	case "hour":
+		out += "/hr"
+	case "day":
+		out += "/day"
+	case "week":
+		out += "/wk"
+	case "month":
+		out += "/mo"
+	}
+	return out
+}
+
+func salaryFromJSONLD(v interface{}) (SalaryRange, bool) {
+	amount, ok := v.(map[string]interface{})
+	if !ok {
+		if text, ok := v.(string); ok {
+			return parseSalary(text)
+		}
+		return SalaryRange{}, false
+	}
+
+	var s SalaryRange
+	if c, ok := amount["currency"].(string); ok {
+		s.Currency = strings.ToUpper(c)
+	}
+
+	value := amount
+	if inner, ok := amount["value"].(map[string]interface{}); ok {
+		value = inner
+	} else if n, ok := jsonNumber(amount["value"]); ok {
+		s.Min, s.Max = n, n
+	}
+
+	if n, ok := jsonNumber(value["minValue"]); ok {
+		s.Min = n
+	}
+	if n, ok := jsonNumber(value["maxValue"]); ok {
+		s.Max = n
+	}
+	if n, ok := jsonNumber(value["value"]); ok && s.IsZero() {
+		s.Min, s.Max = n, n
+	}
+	if s.Max == 0 {
+		s.Max = s.Min
+	}
+	if s.Min == 0 {
+		s.Min = s.Max
+	}
+	if s.IsZero() {
+		return SalaryRange{}, false
+	}
+
+	unit, _ := value["unitText"].(string)
+	if unit == "" {
+		unit, _ = amount["unitText"].(string)
+	}
+	switch strings.ToUpper(unit) {
+	case "HOUR":
+		s.Period = "hour"
+	case "DAY":
+		s.Period = "day"
+	case "WEEK":
+		s.Period = "week"
+	case "MONTH":
+		s.Period = "month"
+	default:
+		s.Period = "year"
+	}
+	if s.Currency == "" {
+		s.Currency = "USD"
+	}
+	return s, true
+}
+
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, n > 0
+	case string:
+		return parseSalaryNumber(strings.TrimSpace(n))
+	}
+	return 0, false
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSalary(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want SalaryRange
+	}{
+		{"k range with en dash", "$150k–$190k", SalaryRange{150000, 190000, "USD", "year"}},
+		{"shared k suffix", "$150-190K", SalaryRange{150000, 190000, "USD", "year"}},
+		{"thousands separators with code", "120,000 - 150,000 USD a year", SalaryRange{120000, 150000, "USD", "year"}},
+		{"serpapi annual", "150K–190K a year", SalaryRange{150000, 190000, "USD", "year"}},
+		{"hourly range", "$45–$60 an hour", SalaryRange{45, 60, "USD", "hour"}},
+		{"hourly decimals", "$52.50/hr", SalaryRange{52.5, 52.5, "USD", "hour"}},
+		{"euro single", "€80K", SalaryRange{80000, 80000, "EUR", "year"}},
+		{"european separators", "€80.000 - €95.000", SalaryRange{80000, 95000, "EUR", "year"}},
+		{"pounds monthly", "£5,000 per month", SalaryRange{5000, 5000, "GBP", "month"}},
+		{"monthly word does not read as millions", "5000 monthly", SalaryRange{5000, 5000, "USD", "month"}},
+		{"cad prefix", "CA$120k - CA$140k", SalaryRange{120000, 140000, "CAD", "year"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseSalary(tt.text)
+			if !ok {
+				t.Fatalf("parseSalary(%q) failed", tt.text)
+			}
+			if got != tt.want {
+				t.Errorf("parseSalary(%q) = %+v, want %+v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSalaryRejectsEmpty(t *testing.T) {
+	for _, text := range []string{"", "Competitive", "DOE"} {
+		if got, ok := parseSalary(text); ok {
+			t.Errorf("parseSalary(%q) = %+v, want no salary", text, got)
+		}
+	}
+}
+
+func TestSalaryAnnual(t *testing.T) {
+	s := SalaryRange{Min: 50, Max: 60, Currency: "USD", Period: "hour"}
+	min, max := s.Annual()
+	if min != 104000 || max != 124800 {
+		t.Errorf("Annual() = %v, %v, want 104000, 124800", min, max)
+	}
+}
+
+func TestSalaryFromJSONLD(t *testing.T) {
+	base := map[string]interface{}{
+		"@type":    "MonetaryAmount",
+		"currency": "usd",
+		"value": map[string]interface{}{
+			"@type":    "QuantitativeValue",
+			"minValue": float64(140000),
+			"maxValue": "175000",
+			"unitText": "YEAR",
+		},
+	}
+	got, ok := salaryFromJSONLD(base)
+	want := SalaryRange{140000, 175000, "USD", "year"}
+	if !ok || got != want {
+		t.Errorf("salaryFromJSONLD() = %+v, %v, want %+v", got, ok, want)
+	}
+}
+
+func TestParseAnnualSalary(t *testing.T) {
+	tests := []struct {
+		text string
+		want SalaryRange
+	}{
+		{"150", SalaryRange{150, 150, "USD", "year"}},
+		{"150k", SalaryRange{150000, 150000, "USD", "year"}},
+		{"€90000", SalaryRange{90000, 90000, "EUR", "year"}},
+		{"$80/hr", SalaryRange{80, 80, "USD", "hour"}},
+	}
+	for _, tt := range tests {
+		if got, ok := parseAnnualSalary(tt.text); !ok || got != tt.want {
+			t.Errorf("parseAnnualSalary(%q) = %+v, %v, want %+v", tt.text, got, ok, tt.want)
+		}
+	}
+}
+
+func TestSalaryFilterAndSortByCurrency(t *testing.T) {
+	jobs := []ScanResult{
+		{Title: "eur high", Pay: SalaryRange{Min: 200000, Max: 200000, Currency: "EUR", Period: "year"}},
+		{Title: "usd low", Pay: SalaryRange{Min: 90000, Max: 90000, Currency: "USD", Period: "year"}},
+		{Title: "none"},
+		{Title: "usd high", Pay: SalaryRange{Min: 180000, Max: 180000, Currency: "USD", Period: "year"}},
+		{Title: "inr", Pay: SalaryRange{Min: 3000000, Max: 3000000, Currency: "INR", Period: "year"}},
+		{Title: "eur low", Pay: SalaryRange{Min: 60000, Max: 60000, Currency: "EUR", Period: "year"}},
+	}
+
+	var titles []string
+	for _, j := range filterBySalary(jobs, 150000, "USD") {
+		titles = append(titles, j.Title)
+	}
+	if got := strings.Join(titles, ","); got != "eur high,none,usd high,inr,eur low" {
+		t.Errorf("filterBySalary = %s", got)
+	}
+
+	sortBySalary(jobs, "USD")
+	titles = nil
+	for _, j := range jobs {
+		titles = append(titles, j.Title)
+	}
+	if got := strings.Join(titles, ","); got != "usd high,usd low,eur high,eur low,inr,none" {
+		t.Errorf("sortBySalary = %s", got)
+	}
+}
@@ -1,29 +1,65 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
-	"regexp"
+	"os/signal"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
-var usStatePattern = regexp.MustCompile(`, (AL|AK|AZ|AR|CA|CO|CT|DE|FL|GA|HI|ID|IL|IN|IA|KS|KY|LA|ME|MD|MA|MI|MN|MS|MO|MT|NE|NV|NH|NJ|NM|NY|NC|ND|OH|OK|OR|PA|RI|SC|SD|TN|TX|UT|VT|VA|WA|WV|WI|WY|DC)(?:\s|$|,)`)
-
 var (
-	scanQuery    string
-	scanBoard    string
-	scanMaxAge   int
-	scanLocation string
+	scanQuery        string
+	scanBoard        string
+	scanMaxAge       int
+	scanLocation     string
+	scanSave         bool
+	scanSaveMinScore int
+	scanMinSalary    string
+	scanSort         string
+	scanExclude      string
+	scanOutput       string
+	scanOut          string
+	scanInteractive  bool
+	scanAutoMatch    int
+	scanMinScore     int
+	scanMatchWorkers int
+	scanNotify       string
+	scanNotifyMin    int
+	scanConcurrency  int
+	scanHostRate     float64
 )
 
+// scanLog receives progress messages so that machine-readable output on
+// stdout stays clean.
+var scanLog io.Writer = os.Stdout
+
 func init() {
 	scanCmd.Flags().StringVarP(&scanQuery, "query", "q", "", "Search keywords (comma-separated)")
 	scanCmd.Flags().StringVarP(&scanBoard, "board", "b", "web3", "Job board (web3)")
 	scanCmd.Flags().IntVar(&scanMaxAge, "max-age", 90, "Maximum job age in days")
-	scanCmd.Flags().StringVarP(&scanLocation, "location", "l", "", "Filter by location (remote, usa, or any text)")
+	scanCmd.Flags().StringVarP(&scanLocation, "location", "l", "", "Filter by location: remote, remote:us, country:ca, region:emea, tz:UTC-8..UTC-5, or any text (comma-separated)")
+	scanCmd.Flags().BoolVar(&scanSave, "save", false, "Save results to the database as discovered jobs")
+	scanCmd.Flags().IntVar(&scanSaveMinScore, "save-min-score", 0, "Only save results scoring at least this much (with --save)")
+	scanCmd.Flags().StringVar(&scanMinSalary, "min-salary", "", "Minimum annual salary, e.g. 150k or €90k (jobs without salary info or in another currency are kept)")
+	scanCmd.Flags().StringVar(&scanSort, "sort", "score", "Sort by: score, salary")
+	scanCmd.Flags().StringVar(&scanExclude, "exclude", "", "Exclusion rules file (default: exclude.json or ~/.resumectl/exclude.json)")
+	scanCmd.Flags().StringVarP(&scanOutput, "output", "o", "table", "Output format: table, json, csv, md")
+	scanCmd.Flags().StringVar(&scanOut, "out", "", "Write results to a file instead of stdout")
+	scanCmd.Flags().BoolVar(&scanInteractive, "interactive", false, "Pick results in a terminal UI and run match on the selected jobs")
+	scanCmd.Flags().IntVar(&scanAutoMatch, "auto-match", 0, "Run match on the top N results and compile PDFs")
+	scanCmd.Flags().IntVar(&scanMinScore, "min-score", 0, "Only auto-match results scoring at least this much (with --auto-match)")
+	scanCmd.Flags().IntVar(&scanMatchWorkers, "match-concurrency", 2, "Number of jobs to match at once (with --auto-match)")
+	scanCmd.Flags().StringVar(&scanNotify, "notify", "", "Send a digest of new results: email, webhook, slack (comma-separated); sent jobs are saved as discovered")
+	scanCmd.Flags().IntVar(&scanNotifyMin, "notify-min-score", 80, "Only include results scoring at least this much in the digest")
+	scanCmd.Flags().IntVar(&scanConcurrency, "concurrency", 4, "Number of concurrent fetch and scoring requests")
+	scanCmd.Flags().Float64Var(&scanHostRate, "host-rate", 0, "Max requests per second to any one host (default: per-host limits)")
 	scanCmd.MarkFlagRequired("query")
 }
 
@@ -34,14 +70,18 @@ var scanCmd = &cobra.Command{
 }
 
 type ScanResult struct {
-	Title    string
-	Company  string
-	URL      string
-	Age      string
-	AgeDays  int
-	Location string
-	Salary   string
-	Score    int
+	Title       string      `json:"title"`
+	Company     string      `json:"company"`
+	URL         string      `json:"url"`
+	Age         string      `json:"age"`
+	AgeDays     int         `json:"age_days"`
+	Location    string      `json:"location"`
+	Salary      string      `json:"salary"`
+	Pay         SalaryRange `json:"pay"`
+	Source      string      `json:"source"`
+	AltURLs     []string    `json:"alt_urls,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Score       int         `json:"score"`
 }
 
 func runScan(cmd *cobra.Command, args []string) {
@@ -56,93 +96,257 @@ func runScan(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	fmt.Printf("Searching %s for: %s\n", scanBoard, strings.Join(keywords, ", "))
+	if scanSort != "score" && scanSort != "salary" {
+		fmt.Fprintf(os.Stderr, "Unknown sort: %s\nAvailable: score, salary\n", scanSort)
+		os.Exit(1)
+	}
+	minSalary := SalaryRange{Currency: "USD"}
+	if scanMinSalary != "" {
+		var ok bool
+		if minSalary, ok = parseAnnualSalary(scanMinSalary); !ok {
+			fmt.Fprintf(os.Stderr, "Invalid --min-salary: %s\n", scanMinSalary)
+			os.Exit(1)
+		}
+	}
+
+	if !validScanOutputs[scanOutput] {
+		fmt.Fprintf(os.Stderr, "Unknown output format: %s\nAvailable: table, json, csv, md\n", scanOutput)
+		os.Exit(1)
+	}
+	if scanOutput != "table" && scanOut == "" {
+		scanLog = os.Stderr
+	}
+	if scanInteractive && scanAutoMatch > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --interactive and --auto-match cannot be combined\n")
+		os.Exit(1)
+	}
+
+	for _, kind := range strings.Split(scanNotify, ",") {
+		if kind = strings.TrimSpace(kind); kind == "" {
+			continue
+		}
+		if _, err := newNotifier(kind); err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid --notify: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	locationFilters, err := parseLocationFilters(scanLocation)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --location: %v\n", err)
+		os.Exit(1)
+	}
+
+	scanLimiter.override = scanHostRate
+
+	// Ctrl-C cancels fetching and scoring; whatever finished is kept.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Fprintf(scanLog, "Searching %s for: %s\n", scanBoard, strings.Join(keywords, ", "))
 
 	var jobs []ScanResult
 
 	switch scanBoard {
 	case "web3":
-		jobs, err = scanWeb3Career(keywords, scanMaxAge)
+		jobs, err = scanWeb3Career(ctx, keywords, scanMaxAge)
 	case "remoteok":
-		jobs, err = scanRemoteOK(keywords, scanMaxAge)
+		jobs, err = scanRemoteOK(ctx, keywords, scanMaxAge)
 	case "google":
-		jobs, err = scanGoogleJobs(keywords, scanMaxAge)
+		jobs, err = scanGoogleJobs(ctx, keywords, scanMaxAge)
 	case "all":
-		jobs, err = scanAllBoards(keywords, scanMaxAge)
+		jobs, err = scanAllBoards(ctx, keywords, scanMaxAge)
 	default:
 		fmt.Fprintf(os.Stderr, "Unknown board: %s\nAvailable: web3, remoteok, google, all\n", scanBoard)
 		os.Exit(1)
 	}
 
-	if err != nil {
+	if err != nil && ctx.Err() == nil {
 		fmt.Fprintf(os.Stderr, "Error scanning: %v\n", err)
 		os.Exit(1)
 	}
 
 	if len(jobs) == 0 {
-		fmt.Println("No jobs found matching criteria.")
+		fmt.Fprintln(scanLog, "No jobs found matching criteria.")
+		writeScanOutput(nil)
 		return
 	}
 
-	if scanLocation != "" {
-		jobs = filterByLocation(jobs, scanLocation)
+	normalizeSalaries(jobs)
+
+	jobs, dupes := mergeDuplicates(jobs)
+	if dupes > 0 {
+		fmt.Fprintf(scanLog, "Merged %d duplicate postings\n", dupes)
+	}
+
+	if scanMinSalary != "" {
+		minAnnual, _ := minSalary.Annual()
+		jobs = filterBySalary(jobs, minAnnual, minSalary.Currency)
 		if len(jobs) == 0 {
-			fmt.Println("No jobs found matching location criteria.")
+			fmt.Fprintln(scanLog, "No jobs found matching salary criteria.")
+			writeScanOutput(nil)
 			return
 		}
 	}
 
-	fmt.Printf("Found %d jobs under %d days old, scoring...\n\n", len(jobs), scanMaxAge)
+	if scanLocation != "" {
+		jobs = filterByLocation(jobs, locationFilters)
+		if len(jobs) == 0 {
+			fmt.Fprintln(scanLog, "No jobs found matching location criteria.")
+			writeScanOutput(nil)
+			return
+		}
+	}
 
-	for i := range jobs {
-		score, err := quickScore(string(resume), jobs[i].Title, jobs[i].Company)
+	rulesPath := scanExclude
+	if rulesPath == "" {
+		rulesPath = findExclusionRules()
+	}
+	if rulesPath != "" {
+		rules, err := loadExclusionRules(rulesPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "  Warning: could not score %s: %v\n", jobs[i].Title, err)
-			continue
+			fmt.Fprintf(os.Stderr, "Error loading exclusion rules: %v\n", err)
+			os.Exit(1)
+		}
+		if rules.RejectedWithinMonths > 0 {
+			if err := InitDB(); err != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: skipping rejected-company rule: %v\n", err)
+			} else if err := rules.loadRejected(); err != nil {
+				fmt.Fprintf(os.Stderr, "  Warning: skipping rejected-company rule: %v\n", err)
+			}
+		}
+
+		var reasons map[string]int
+		jobs, reasons = applyExclusions(jobs, rules)
+		printExclusions(scanLog, reasons)
+		printDescriptionGaps(scanLog, jobs, rules)
+		if len(jobs) == 0 {
+			fmt.Fprintln(scanLog, "All jobs were excluded.")
+			writeScanOutput(nil)
+			return
 		}
-		jobs[i].Score = score
 	}
 
-	sortByScore(jobs)
-	printScanResults(jobs)
-}
+	fmt.Fprintf(scanLog, "Found %d jobs under %d days old, scoring...\n", len(jobs), scanMaxAge)
+	jobs = scoreScanResults(ctx, string(resume), jobs)
+	fmt.Fprintln(scanLog)
 
-func scanAllBoards(keywords []string, maxAgeDays int) ([]ScanResult, error) {
-	var all []ScanResult
-	seen := make(map[string]bool)
+	interrupted := ctx.Err() != nil
+	stop()
+	if interrupted {
+		fmt.Fprintf(scanLog, "%s Interrupted, keeping %d scored results\n\n", color.YellowString("⚠"), len(jobs))
+	}
 
-	web3Jobs, err := scanWeb3Career(keywords, maxAgeDays)
-	if err != nil {
-		fmt.Printf("  web3.career: %v\n", err)
+	if scanSort == "salary" {
+		sortBySalary(jobs, minSalary.Currency)
 	} else {
-		fmt.Printf("  web3.career: %d jobs\n", len(web3Jobs))
-		for _, j := range web3Jobs {
-			if !seen[j.URL] {
-				seen[j.URL] = true
-				all = append(all, j)
-			}
+		sortByScore(jobs)
+	}
+	if !scanInteractive || scanOut != "" {
+		writeScanOutput(jobs)
+	}
+
+	if scanNotify != "" {
+		notifyNewResults(jobs)
+	}
+
+	if scanSave {
+		saveScanResults(jobs, scanSaveMinScore)
+	}
+
+	if interrupted {
+		return
+	}
+
+	template := ""
+	if cmd.Flags().Changed("resume") {
+		template = resumePath
+	} else if scanInteractive || scanAutoMatch > 0 {
+		if err := recordTemplateEdits(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record template edits: %v\n", err)
 		}
 	}
+	if scanInteractive {
+		runInteractiveMatch(jobs, template)
+	}
+	if scanAutoMatch > 0 {
+		runAutoMatch(jobs, scanAutoMatch, scanMinScore, scanMatchWorkers, template)
+	}
+}
 
-	remoteJobs, err := scanRemoteOK(keywords, maxAgeDays)
-	if err != nil {
-		fmt.Printf("  remoteok: %v\n", err)
-	} else {
-		fmt.Printf("  remoteok: %d jobs\n", len(remoteJobs))
-		for _, j := range remoteJobs {
-			if !seen[j.URL] {
-				seen[j.URL] = true
-				all = append(all, j)
-			}
+func notifyNewResults(jobs []ScanResult) {
+	if err := InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "  Warning: could not init database, every result counts as new: %v\n", err)
+	}
+	fresh := newScanResults(jobs, scanNotifyMin)
+	if len(fresh) == 0 {
+		fmt.Fprintf(scanLog, "\nNo new jobs scoring %d or higher, skipping notifications.\n", scanNotifyMin)
+		return
+	}
+	fmt.Fprintln(scanLog)
+	sent := sendScanDigest(scanNotify, ScanDigest{
+		Query:       scanQuery,
+		Board:       scanBoard,
+		MinScore:    scanNotifyMin,
+		GeneratedAt: time.Now(),
+		Jobs:        fresh,
+	})
+	if !sent || db == nil {
+		return
+	}
+	for _, j := range fresh {
+		if err := SaveDiscoveredJob(j); err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: could not record %s as notified, it may be sent again: %v\n", j.URL, err)
 		}
 	}
+}
 
-	googleJobs, err := scanGoogleJobs(keywords, maxAgeDays)
-	if err != nil {
-		fmt.Printf("  google: %v\n", err)
-	} else {
-		fmt.Printf("  google: %d jobs\n", len(googleJobs))
-		for _, j := range googleJobs {
+func saveScanResults(jobs []ScanResult, minScore int) {
+	if err := InitDB(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: could not init database: %v\n", err)
+		os.Exit(1)
+	}
+
+	saved := 0
+	for _, j := range jobs {
+		if j.Score < minScore || j.URL == "" {
+			continue
+		}
+		if err := SaveDiscoveredJob(j); err != nil {
+			fmt.Fprintf(os.Stderr, "  Warning: could not save %s: %v\n", j.URL, err)
+			continue
+		}
+		saved++
+	}
+	fmt.Fprintf(scanLog, "\n%s Saved %d jobs as discovered\n", color.GreenString("✓"), saved)
+}
+
+var scanBoards = []struct {
+	name string
+	scan func(ctx context.Context, keywords []string, maxAgeDays int) ([]ScanResult, error)
+}{
+	{"web3.career", scanWeb3Career},
+	{"remoteok", scanRemoteOK},
+	{"google", scanGoogleJobs},
+}
+
+func scanAllBoards(ctx context.Context, keywords []string, maxAgeDays int) ([]ScanResult, error) {
+	results := make([][]ScanResult, len(scanBoards))
+	errs := make([]error, len(scanBoards))
+
+	runPool(ctx, scanConcurrency, len(scanBoards), func(i int) {
+		results[i], errs[i] = scanBoards[i].scan(ctx, keywords, maxAgeDays)
+	})
+
+	var all []ScanResult
+	seen := make(map[string]bool)
+	for i, board := range scanBoards {
+		if errs[i] != nil {
+			fmt.Fprintf(scanLog, "  %s: %v\n", board.name, errs[i])
+			continue
+		}
+		fmt.Fprintf(scanLog, "  %s: %d jobs\n", board.name, len(results[i]))
+		for _, j := range results[i] {
 			if !seen[j.URL] {
 				seen[j.URL] = true
 				all = append(all, j)
@@ -153,99 +357,136 @@ func scanAllBoards(keywords []string, maxAgeDays int) ([]ScanResult, error) {
 	return all, nil
 }
 
-func filterByLocation(jobs []ScanResult, filter string) []ScanResult {
-	filters := strings.Split(strings.ToLower(filter), ",")
-	for i := range filters {
-		filters[i] = strings.TrimSpace(filters[i])
+// scoreScanResults scores jobs with a bounded worker pool. If ctx is
+// cancelled part way, only the jobs that were scored are returned; if it was
+// cancelled while fetching, the unscored results are returned as-is.
+func scoreScanResults(ctx context.Context, resume string, jobs []ScanResult) []ScanResult {
+	if ctx.Err() != nil {
+		return jobs
+	}
+	scored := make([]bool, len(jobs))
+	progress := newScanProgress(scanLog, "  Scoring", len(jobs))
+
+	runPool(ctx, scanConcurrency, len(jobs), func(i int) {
+		score, err := quickScore(ctx, resume, jobs[i].Title, jobs[i].Company)
+		if err != nil {
+			if ctx.Err() == nil {
+				fmt.Fprintf(os.Stderr, "\r  Warning: could not score %s: %v\n", jobs[i].Title, err)
+				jobs[i].Score = 0
+				scored[i] = true
+				progress.inc()
+			}
+			return
+		}
+		jobs[i].Score = score
+		scored[i] = true
+		progress.inc()
+	})
+	progress.finish()
+
+	if ctx.Err() == nil {
+		return jobs
 	}
+	var kept []ScanResult
+	for i, j := range jobs {
+		if scored[i] {
+			kept = append(kept, j)
+		}
+	}
+	return kept
+}
 
+func filterByLocation(jobs []ScanResult, filters []locationFilter) []ScanResult {
 	var filtered []ScanResult
 
 	for _, j := range jobs {
-		loc := strings.ToLower(j.Location)
-
-		match := false
+		loc := parseLocation(j.Location)
 		for _, f := range filters {
-			switch f {
-			case "remote":
-				match = strings.Contains(loc, "remote") || strings.Contains(loc, "anywhere")
-			case "usa", "us":
-				match = strings.Contains(loc, "united states") ||
-					strings.Contains(loc, ", us") ||
-					strings.Contains(loc, "usa") ||
-					strings.Contains(loc, "anywhere") ||
-					usStatePattern.MatchString(loc) ||
-					strings.Contains(loc, "new york") ||
-					strings.Contains(loc, "san francisco") ||
-					strings.Contains(loc, "los angeles") ||
-					strings.Contains(loc, "seattle") ||
-					strings.Contains(loc, "austin") ||
-					strings.Contains(loc, "denver") ||
-					strings.Contains(loc, "chicago") ||
-					strings.Contains(loc, "miami") ||
-					strings.Contains(loc, "boston") ||
-					strings.Contains(loc, "tampa") ||
-					strings.Contains(loc, "washington")
-			default:
-				match = strings.Contains(loc, f)
-			}
-			if match {
+			if f.matches(loc) {
+				filtered = append(filtered, j)
 				break
 			}
 		}
-
-		if match {
-			filtered = append(filtered, j)
-		}
 	}
 
 	return filtered
 }
 
-func sortByScore(jobs []ScanResult) {
-	for i := 0; i < len(jobs)-1; i++ {
-		for j := i + 1; j < len(jobs); j++ {
-			if jobs[j].Score > jobs[i].Score {
-				jobs[i], jobs[j] = jobs[j], jobs[i]
-			}
+func normalizeSalaries(jobs []ScanResult) {
+	for i := range jobs {
+		if !jobs[i].Pay.IsZero() {
+			continue
+		}
+		if pay, ok := parseSalary(jobs[i].Salary); ok {
+			jobs[i].Pay = pay
 		}
 	}
 }
 
-func printScanResults(jobs []ScanResult) {
-	fmt.Printf("%-6s %-12s %-15s %-28s %-15s %s\n", "Score", "Salary", "Company", "Title", "Location", "URL")
-	fmt.Println(strings.Repeat("─", 130))
-
+// filterBySalary drops jobs paying less than minAnnual in currency. Jobs
+// without salary info or paid in another currency can't be compared and
+// are kept.
+func filterBySalary(jobs []ScanResult, minAnnual float64, currency string) []ScanResult {
+	var filtered []ScanResult
 	for _, j := range jobs {
-		company := j.Company
-		if len(company) > 15 {
-			company = company[:12] + "..."
+		if j.Pay.IsZero() || j.Pay.Currency != currency {
+			filtered = append(filtered, j)
+			continue
 		}
-		title := j.Title
-		if len(title) > 28 {
-			title = title[:25] + "..."
+		if _, max := j.Pay.Annual(); max >= minAnnual {
+			filtered = append(filtered, j)
 		}
-		location := j.Location
-		if len(location) > 15 {
-			location = location[:12] + "..."
+	}
+	return filtered
+}
+
+// sortBySalary puts jobs paid in currency first, highest annual pay first,
+// then other currencies grouped by code and sorted within each, then jobs
+// without salary info.
+func sortBySalary(jobs []ScanResult, currency string) {
+	group := func(j ScanResult) int {
+		switch {
+		case j.Pay.IsZero():
+			return 2
+		case j.Pay.Currency == currency:
+			return 0
 		}
-		salary := j.Salary
-		if salary == "" {
-			salary = "-"
+		return 1
+	}
+	sort.SliceStable(jobs, func(a, b int) bool {
+		ja, jb := jobs[a], jobs[b]
+		if ga, gb := group(ja), group(jb); ga != gb {
+			return ga < gb
 		}
-		if len(salary) > 12 {
-			salary = salary[:12]
+		if ja.Pay.Currency != jb.Pay.Currency {
+			return ja.Pay.Currency < jb.Pay.Currency
 		}
-
-		scoreStr := fmt.Sprintf("%d", j.Score)
-		if j.Score >= 80 {
-			scoreStr = color.GreenString("%d", j.Score)
-		} else if j.Score >= 60 {
-			scoreStr = color.YellowString("%d", j.Score)
-		} else {
-			scoreStr = color.RedString("%d", j.Score)
+		_, sa := ja.Pay.Annual()
+		_, sb := jb.Pay.Annual()
+		if sa != sb {
+			return sa > sb
 		}
+		return scanResultLess(ja, jb)
+	})
+}
+
+func sortByScore(jobs []ScanResult) {
+	sort.SliceStable(jobs, func(a, b int) bool {
+		return scanResultLess(jobs[a], jobs[b])
+	})
+}
 
-		fmt.Printf("%-6s %-12s %-15s %-28s %-15s %s\n", scoreStr, salary, company, title, location, j.URL)
+// scanResultLess orders by score, then company, title and URL so results
+// come out the same regardless of the order workers finished in.
+func scanResultLess(a, b ScanResult) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Company != b.Company {
+		return a.Company < b.Company
+	}
+	if a.Title != b.Title {
+		return a.Title < b.Title
 	}
+	return a.URL < b.URL
 }
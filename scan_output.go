@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var validScanOutputs = map[string]bool{"table": true, "json": true, "csv": true, "md": true}
+
+func writeScanOutput(jobs []ScanResult) {
+	if scanOut == "" && scanOutput == "table" && len(jobs) == 0 {
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if scanOut != "" {
+		f, err := os.Create(scanOut)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating %s: %v\n", scanOut, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		w = f
+		noColor := color.NoColor
+		color.NoColor = true
+		defer func() { color.NoColor = noColor }()
+	}
+
+	var err error
+	switch scanOutput {
+	case "json":
+		err = writeScanJSON(w, jobs)
+	case "csv":
+		err = writeScanCSV(w, jobs)
+	case "md":
+		err = writeScanMarkdown(w, jobs)
+	default:
+		printScanResults(w, jobs)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing results: %v\n", err)
+		os.Exit(1)
+	}
+
+	if scanOut != "" {
+		fmt.Fprintf(scanLog, "%s Wrote %d results to %s\n", color.GreenString("✓"), len(jobs), scanOut)
+	}
+}
+
+func writeScanJSON(w io.Writer, jobs []ScanResult) error {
+	if jobs == nil {
+		jobs = []ScanResult{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jobs)
+}
+
+var scanCSVHeader = []string{
+	"score", "title", "company", "location", "salary", "salary_min", "salary_max",
+	"salary_currency", "salary_period", "source", "age_days", "url", "alt_urls",
+}
+
+func writeScanCSV(w io.Writer, jobs []ScanResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write(scanCSVHeader)
+	for _, j := range jobs {
+		var min, max string
+		if !j.Pay.IsZero() {
+			min = strconv.FormatFloat(j.Pay.Min, 'f', -1, 64)
+			max = strconv.FormatFloat(j.Pay.Max, 'f', -1, 64)
+		}
+		cw.Write([]string{
+			strconv.Itoa(j.Score), j.Title, j.Company, j.Location, j.Salary, min, max,
+			j.Pay.Currency, j.Pay.Period, j.Source, strconv.Itoa(j.AgeDays), j.URL,
+			strings.Join(j.AltURLs, " "),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeScanMarkdown(w io.Writer, jobs []ScanResult) error {
+	fmt.Fprintln(w, "| Score | Title | Company | Location | Salary | Source | Age (days) | URL |")
+	fmt.Fprintln(w, "|------:|-------|---------|----------|--------|--------|-----------:|-----|")
+	for _, j := range jobs {
+		salary := j.Pay.String()
+		if salary == "" {
+			salary = j.Salary
+		}
+		_, err := fmt.Fprintf(w, "| %d | %s | %s | %s | %s | %s | %d | %s |\n",
+			j.Score, markdownCell(j.Title), markdownCell(j.Company), markdownCell(j.Location),
+			markdownCell(salary), markdownCell(j.Source), j.AgeDays, markdownCell(j.URL))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func printScanResults(w io.Writer, jobs []ScanResult) {
+	fmt.Fprintf(w, "%-6s %-12s %-15s %-28s %-15s %s\n", "Score", "Salary", "Company", "Title", "Location", "URL")
+	fmt.Fprintln(w, strings.Repeat("─", 130))
+
+	for _, j := range jobs {
+		company := j.Company
+		if len(company) > 15 {
+			company = company[:12] + "..."
+		}
+		title := j.Title
+		if len(title) > 28 {
+			title = title[:25] + "..."
+		}
+		location := j.Location
+		if len(location) > 15 {
+			location = location[:12] + "..."
+		}
+		salary := j.Pay.String()
+		if salary == "" {
+			salary = j.Salary
+		}
+		if salary == "" {
+			salary = "-"
+		}
+		if len(salary) > 12 {
+			salary = salary[:12]
+		}
+
+		scoreStr := fmt.Sprintf("%d", j.Score)
+		if j.Score >= 80 {
+			scoreStr = color.GreenString("%d", j.Score)
+		} else if j.Score >= 60 {
+			scoreStr = color.YellowString("%d", j.Score)
+		} else {
+			scoreStr = color.RedString("%d", j.Score)
+		}
+
+		fmt.Fprintf(w, "%-6s %-12s %-15s %-28s %-15s %s\n", scoreStr, salary, company, title, location, j.URL)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var outputFixture = []ScanResult{{
+	Title:    "Senior Data Engineer, Platform | Infrastructure",
+	Company:  "A Very Long Company Name Inc",
+	URL:      "https://example.com/jobs/1",
+	AgeDays:  3,
+	Location: "Remote - US",
+	Salary:   "$150k - $190k",
+	Pay:      SalaryRange{Min: 150000, Max: 190000, Currency: "USD", Period: "year"},
+	Source:   "remoteok,google",
+	AltURLs:  []string{"https://example.org/1"},
+	Score:    84,
+}}
+
+func TestWriteScanJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeScanJSON(&buf, outputFixture); err != nil {
+		t.Fatal(err)
+	}
+	var got []ScanResult
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Title != outputFixture[0].Title || got[0].AgeDays != 3 || got[0].Pay.Max != 190000 {
+		t.Errorf("round trip = %+v", got)
+	}
+
+	buf.Reset()
+	writeScanJSON(&buf, nil)
+	if strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("empty output = %q, want []", buf.String())
+	}
+}
+
+func TestWriteScanCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeScanCSV(&buf, outputFixture); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	row := records[1]
+	if row[0] != "84" || row[1] != outputFixture[0].Title || row[5] != "150000" || row[9] != "remoteok,google" || row[10] != "3" {
+		t.Errorf("row = %q", row)
+	}
+}
+
+func TestWriteScanMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	writeScanMarkdown(&buf, outputFixture)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(lines))
+	}
+	if !strings.Contains(lines[2], `Platform \| Infrastructure`) || !strings.Contains(lines[2], "A Very Long Company Name Inc") {
+		t.Errorf("row = %q", lines[2])
+	}
+}
@@ -3,9 +3,9 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 
@@ -34,6 +34,7 @@ func runServe(cmd *cobra.Command, args []string) {
 	mux.HandleFunc("/match", authMiddleware(handleMatch))
 	mux.HandleFunc("/pipeline", authMiddleware(handlePipeline))
 	mux.HandleFunc("/pdf/", authMiddleware(handlePDFDownload))
+	mux.HandleFunc("/files/", authMiddleware(handleFileDownload))
 	mux.HandleFunc("/health", handleHealth)
 
 	addr := fmt.Sprintf(":%d", servePort)
@@ -67,9 +68,13 @@ func handleMatch(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req struct {
-		URL     string `json:"url"`
-		File    string `json:"file"`
-		Company string `json:"company"`
+		URL      string   `json:"url"`
+		File     string   `json:"file"`
+		Company  string   `json:"company"`
+		Mode     string   `json:"mode"`
+		Strict   bool     `json:"strict"`
+		MaxPages *int     `json:"max_pages"`
+		Formats  []string `json:"formats"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, `{"error":"invalid json"}`, http.StatusBadRequest)
@@ -80,6 +85,14 @@ func handleMatch(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, `{"error":"url or file required"}`, http.StatusBadRequest)
 		return
 	}
+	if err := checkFormats(req.Formats); err != nil {
+		http.Error(w, fmt.Sprintf(`{"error":%q}`, err.Error()), http.StatusBadRequest)
+		return
+	}
+	if req.Mode != "" && req.Mode != "rewrite" && req.Mode != "reorder" {
+		http.Error(w, fmt.Sprintf(`{"error":"unknown mode %q: must be rewrite or reorder"}`, req.Mode), http.StatusBadRequest)
+		return
+	}
 
 	var job *JobInfo
 	var err error
@@ -102,15 +115,24 @@ func handleMatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	templates, _ := findTemplates()
-	if len(templates) == 0 {
-		http.Error(w, `{"error":"no resume templates found"}`, http.StatusInternalServerError)
-		return
-	}
-
-	bestTemplate := templates[0]
-	if len(templates) > 1 {
-		bestTemplate = selectBestTemplateFromList(templates, job)
+	var bestTemplate string
+	var ranking []templateRank
+	if bank := findExperienceBank(); bank != "" {
+		bestTemplate, _, err = assembleFromBank(bank, job, io.Discard)
+		if err != nil {
+			http.Error(w, fmt.Sprintf(`{"error":"could not assemble resume: %s"}`, err), http.StatusInternalServerError)
+			return
+		}
+	} else {
+		templates, _ := loadTemplates()
+		if len(templates) == 0 {
+			http.Error(w, `{"error":"no resume templates found"}`, http.StatusInternalServerError)
+			return
+		}
+		bestTemplate = templates[0].Path
+		if len(templates) > 1 {
+			bestTemplate, ranking = selectBestTemplateFromList(templates, job)
+		}
 	}
 
 	resume, err := os.ReadFile(bestTemplate)
@@ -119,58 +141,87 @@ func handleMatch(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := analyzeAndTailor(string(resume), job.Description)
+	tailor := analyzeAndTailor
+	if req.Mode == "reorder" {
+		tailor = analyzeAndReorder
+	}
+	result, err := tailor(string(resume), job.Description)
 	if err != nil {
 		http.Error(w, fmt.Sprintf(`{"error":"analysis failed: %s"}`, err), http.StatusInternalServerError)
 		return
 	}
+	result.TailoredLatex, result.LockViolations = enforceLocks(string(resume), result.TailoredLatex)
+	result.Drift = detectDrift(string(resume), result.TailoredLatex)
 
-	if db != nil {
-		SaveJob(req.URL, job.Company, job.Title, result.Score)
+	rejectDrift := func() bool {
+		if !req.Strict || len(result.Drift) == 0 {
+			return false
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": fmt.Sprintf("%d drift findings", len(result.Drift)),
+			"drift": result.Drift,
+		})
+		return true
+	}
+	if rejectDrift() {
+		return
 	}
 
 	outputDir := generateOutputDir(job)
-	os.MkdirAll(outputDir, 0755)
-	os.WriteFile(outputDir+"/resume.tex", []byte(result.TailoredLatex), 0644)
-	os.WriteFile(outputDir+"/job.txt", []byte(job.Description), 0644)
+	limit := 0
+	if req.MaxPages != nil {
+		limit = *req.MaxPages
+	}
+	compiled := fitMatchResult(outputDir, string(resume), result, limit, io.Discard)
+	writeMatchOutputs(outputDir, job, result)
 
-	report := fmt.Sprintf("Score: %d/100\n\nStrong Matches:\n", result.Score)
-	for _, m := range result.StrongMatches {
-		report += fmt.Sprintf("  - %s\n", m)
+	pdfURL, compileErr := "", ""
+	if !compiled {
+		err = compileMatchOutputs(outputDir, string(resume), job, result)
 	}
-	report += "\nGaps:\n"
-	for _, g := range result.Gaps {
-		report += fmt.Sprintf("  - %s\n", g)
+	if err != nil {
+		compileErr = err.Error()
+	} else {
+		pdfURL = fmt.Sprintf("/pdf/%s/resume.pdf", outputDir)
 	}
-	os.WriteFile(outputDir+"/report.txt", []byte(report), 0644)
 
-	clsFiles, _ := filepath.Glob("*.cls")
-	if len(clsFiles) == 0 {
-		home, _ := os.UserHomeDir()
-		clsFiles, _ = filepath.Glob(filepath.Join(home, ".resumectl", "*.cls"))
+	// A LaTeX repair can change the source, so drift is checked again.
+	if rejectDrift() {
+		return
 	}
-	for _, cls := range clsFiles {
-		src, _ := os.ReadFile(cls)
-		os.WriteFile(filepath.Join(outputDir, filepath.Base(cls)), src, 0644)
+	if db != nil {
+		SaveJob(req.URL, job.Company, job.Title, job.Description, result.Score)
 	}
-
-	pdfURL := ""
-	cmd := exec.Command("tectonic", "resume.tex")
-	cmd.Dir = outputDir
-	if err := cmd.Run(); err == nil {
-		pdfURL = fmt.Sprintf("/pdf/%s/resume.pdf", outputDir)
+	downloads := map[string]string{}
+	formats, err := writeResumeFormats(outputDir, result.TailoredLatex, req.Formats)
+	for _, f := range formats {
+		downloads[strings.TrimPrefix(filepath.Ext(f), ".")] = fmt.Sprintf("/files/%s/%s", outputDir, f)
+	}
+	formatErr := ""
+	if err != nil {
+		formatErr = err.Error()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"score":          result.Score,
-		"company":        job.Company,
-		"title":          job.Title,
-		"strong_matches": result.StrongMatches,
-		"gaps":           result.Gaps,
-		"template_used":  bestTemplate,
-		"output_dir":     outputDir,
-		"pdf_url":        pdfURL,
+		"score":            result.Score,
+		"company":          job.Company,
+		"title":            job.Title,
+		"strong_matches":   result.StrongMatches,
+		"gaps":             result.Gaps,
+		"template_used":    bestTemplate,
+		"template_ranking": ranking,
+		"output_dir":       outputDir,
+		"pdf_url":          pdfURL,
+		"drift":            result.Drift,
+		"lock_violations":  result.LockViolations,
+		"trimmed":          result.Trimmed,
+		"latex_fixes":      result.LatexFixes,
+		"compile_error":    compileErr,
+		"downloads":        downloads,
+		"format_error":     formatErr,
 	})
 }
 
@@ -229,8 +280,8 @@ func handlePipeline(w http.ResponseWriter, r *http.Request) {
 }
 
 func handlePDFDownload(w http.ResponseWriter, r *http.Request) {
-	path := strings.TrimPrefix(r.URL.Path, "/pdf/")
-	if !strings.HasSuffix(path, ".pdf") || strings.Contains(path, "..") {
+	path, ok := resultsPath(strings.TrimPrefix(r.URL.Path, "/pdf/"))
+	if !ok || !strings.HasSuffix(path, ".pdf") {
 		http.Error(w, "not found", http.StatusNotFound)
 		return
 	}
@@ -246,51 +297,41 @@ func handlePDFDownload(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-func findTemplates() ([]string, error) {
-	templates, err := filepath.Glob("resume.template*.tex")
-	if err != nil || len(templates) == 0 {
-		home, _ := os.UserHomeDir()
-		templates, err = filepath.Glob(home + "/.resumectl/resume.template*.tex")
+// resultsPath cleans a requested download path and reports whether it is a
+// relative path inside the results directory.
+func resultsPath(p string) (string, bool) {
+	p = filepath.Clean(filepath.FromSlash(p))
+	if filepath.IsAbs(p) || !strings.HasPrefix(p, "results"+string(filepath.Separator)) {
+		return "", false
 	}
-	return templates, err
+	return p, true
 }
 
-func selectBestTemplateFromList(templates []string, job *JobInfo) string {
-	var results []scoredTemplate
-	for _, t := range templates {
-		resume, err := os.ReadFile(t)
-		if err != nil {
-			continue
-		}
-		label := templateLabel(t)
-		score, err := scoreTemplate(string(resume), job.Title, job.Description, label)
-		if err != nil {
-			continue
-		}
-		results = append(results, scoredTemplate{t, label, score})
+// handleFileDownload serves resume.<format> from a results directory.
+func handleFileDownload(w http.ResponseWriter, r *http.Request) {
+	path, ok := resultsPath(strings.TrimPrefix(r.URL.Path, "/files/"))
+	name := filepath.Base(path)
+	contentType, known := formatContentTypes[strings.TrimPrefix(filepath.Ext(name), ".")]
+	if !ok || !known || strings.TrimSuffix(name, filepath.Ext(name)) != "resume" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
 	}
 
-	if len(results) == 0 {
-		return templates[0]
+	data, err := os.ReadFile(path)
+	if err != nil {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
 	}
 
-	best := results[0]
-	tied := false
-	for _, r := range results[1:] {
-		if r.score > best.score {
-			best = r
-			tied = false
-		} else if r.score == best.score {
-			tied = true
-		}
-	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.Write(data)
+}
 
-	if tied {
-		winner, err := compareTemplates(results, job.Title, job.Description)
-		if err == nil {
-			best = winner
-		}
+func selectBestTemplateFromList(templates []Template, job *JobInfo) (string, []templateRank) {
+	ranking, err := rankTemplates(templates, job)
+	if err != nil {
+		return templates[0].Path, nil
 	}
-
-	return best.path
+	return ranking[0].Path, ranking
 }
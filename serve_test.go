@@ -0,0 +1,27 @@
+package main
+
+import "testing"
+
+func TestResultsPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"results/acme/backend/resume.docx", "results/acme/backend/resume.docx", true},
+		{"results/acme//backend/./resume.pdf", "results/acme/backend/resume.pdf", true},
+		{"results/../resume.tex", "", false},
+		{"results/acme/../../etc/resume.txt", "", false},
+		{"/etc/resume.txt", "", false},
+		{"/results/acme/resume.pdf", "", false},
+		{"templates/resume.tex", "", false},
+		{"results", "", false},
+		{"resultsx/resume.pdf", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := resultsPath(tt.path)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("resultsPath(%q) = %q, %v, want %q, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -37,7 +37,7 @@ func runSync(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	all, err := ListJobs("", 0)
+	all, err := ListJobs("", 0, "")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error loading jobs: %v\n", err)
 		os.Exit(1)
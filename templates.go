@@ -0,0 +1,560 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+// Templates are registered in ~/.resumectl/templates as <label>.tex. Each
+// starts with a front-matter comment block, so it still compiles as is:
+//
+//	% ---
+//	% focus: data platform
+//	% roles: Data Engineer, Staff Data Engineer
+//	% keywords: Kafka, Snowflake, Airflow
+//	% edited: 2026-10-18
+//	% ---
+//
+// Every version a template has had is kept in .history/<label>/<n>.tex.
+const frontMatterFence = "% ---"
+
+type TemplateMeta struct {
+	Focus    string
+	Roles    []string
+	Keywords []string
+	Edited   time.Time
+}
+
+type Template struct {
+	Label string
+	Path  string
+	Meta  TemplateMeta
+}
+
+type templateVersion struct {
+	N    int
+	Path string
+	Hash string
+	Time time.Time
+}
+
+var (
+	templateAddLabel    string
+	templateFocus       string
+	templateRoles       []string
+	templateKeywords    []string
+	templateRename      string
+	templateShowVersion int
+	templatePurge       bool
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage resume templates and their metadata",
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := recordTemplateEdits(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not record template edits: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List templates with their focus, target roles and versions",
+		Args:  cobra.NoArgs,
+		Run:   runTemplateList,
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <file.tex>",
+		Short: "Register a template, or add a new version of an existing one",
+		Args:  cobra.ExactArgs(1),
+		Run:   runTemplateAdd,
+	}
+	addCmd.Flags().StringVar(&templateAddLabel, "label", "", "Template label (default: from the filename)")
+	addTemplateMetaFlags(addCmd)
+
+	showCmd := &cobra.Command{
+		Use:   "show <label>",
+		Short: "Show a template's metadata and version history",
+		Args:  cobra.ExactArgs(1),
+		Run:   runTemplateShow,
+	}
+	showCmd.Flags().IntVar(&templateShowVersion, "version", 0, "Print the LaTeX of this version")
+
+	labelCmd := &cobra.Command{
+		Use:   "label <label>",
+		Short: "Change a template's focus, target roles, keywords or label",
+		Args:  cobra.ExactArgs(1),
+		Run:   runTemplateLabel,
+	}
+	addTemplateMetaFlags(labelCmd)
+	labelCmd.Flags().StringVar(&templateRename, "rename", "", "New label")
+
+	removeCmd := &cobra.Command{
+		Use:   "remove <label>",
+		Short: "Unregister a template (its history is kept)",
+		Args:  cobra.ExactArgs(1),
+		Run:   runTemplateRemove,
+	}
+	removeCmd.Flags().BoolVar(&templatePurge, "purge", false, "Delete the version history too")
+
+	templateCmd.AddCommand(listCmd, addCmd, showCmd, labelCmd, removeCmd)
+}
+
+func addTemplateMetaFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&templateFocus, "focus", "", "Focus area, e.g. \"data platform\"")
+	cmd.Flags().StringSliceVar(&templateRoles, "roles", nil, "Target roles, comma-separated")
+	cmd.Flags().StringSliceVar(&templateKeywords, "keywords", nil, "Keywords, comma-separated")
+}
+
+func templatesDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".resumectl", "templates")
+}
+
+func templateHistoryDir(label string) string {
+	return filepath.Join(templatesDir(), ".history", label)
+}
+
+// parseFrontMatter returns the metadata at the top of src and the LaTeX
+// after it. Without a complete front-matter block the metadata is empty and
+// body is src.
+func parseFrontMatter(src string) (meta TemplateMeta, body string) {
+	lines := strings.SplitAfter(src, "\n")
+	if strings.TrimSpace(lines[0]) != frontMatterFence {
+		return TemplateMeta{}, src
+	}
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == frontMatterFence {
+			return meta, strings.Join(lines[i+1:], "")
+		}
+		if !strings.HasPrefix(line, "%") {
+			break
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "%"), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "focus":
+			meta.Focus = value
+		case "roles":
+			meta.Roles = splitMetaList(value)
+		case "keywords":
+			meta.Keywords = splitMetaList(value)
+		case "edited":
+			meta.Edited, _ = time.Parse(time.DateOnly, value)
+		}
+	}
+	return TemplateMeta{}, src
+}
+
+func splitMetaList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// withFrontMatter puts meta at the top of body, replacing any front matter
+// body already has.
+func withFrontMatter(meta TemplateMeta, body string) string {
+	_, body = parseFrontMatter(body)
+	var b strings.Builder
+	b.WriteString(frontMatterFence + "\n")
+	fmt.Fprintf(&b, "%% focus: %s\n", meta.Focus)
+	if len(meta.Roles) > 0 {
+		fmt.Fprintf(&b, "%% roles: %s\n", strings.Join(meta.Roles, ", "))
+	}
+	if len(meta.Keywords) > 0 {
+		fmt.Fprintf(&b, "%% keywords: %s\n", strings.Join(meta.Keywords, ", "))
+	}
+	if !meta.Edited.IsZero() {
+		fmt.Fprintf(&b, "%% edited: %s\n", meta.Edited.Format(time.DateOnly))
+	}
+	b.WriteString(frontMatterFence + "\n")
+	return b.String() + body
+}
+
+// targets describes the roles and keywords a template is aimed at, as
+// sentences to append to a scoring prompt.
+func (m TemplateMeta) targets() string {
+	var s string
+	if len(m.Roles) > 0 {
+		s += fmt.Sprintf(" It targets these roles: %s.", strings.Join(m.Roles, ", "))
+	}
+	if len(m.Keywords) > 0 {
+		s += fmt.Sprintf(" Its keywords: %s.", strings.Join(m.Keywords, ", "))
+	}
+	return s
+}
+
+// loadTemplates returns the registered templates sorted by label. With
+// none registered it falls back to resume.template*.tex in the working
+// directory or ~/.resumectl, labelled by filename.
+func loadTemplates() ([]Template, error) {
+	paths, err := filepath.Glob(filepath.Join(templatesDir(), "*.tex"))
+	if err != nil {
+		return nil, err
+	}
+	registered := len(paths) > 0
+	if !registered {
+		paths = findLegacyTemplates()
+	}
+
+	var templates []Template
+	for _, p := range paths {
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		t := Template{Label: strings.TrimSuffix(filepath.Base(p), ".tex"), Path: p}
+		if !registered {
+			t.Label = templateLabel(p)
+		}
+		t.Meta, _ = parseFrontMatter(string(src))
+		if t.Meta.Focus == "" {
+			t.Meta.Focus = t.Label
+		}
+		if info, err := os.Stat(p); err == nil && info.ModTime().After(t.Meta.Edited) {
+			t.Meta.Edited = info.ModTime()
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Label < templates[j].Label })
+	return templates, nil
+}
+
+// recordTemplateEdits saves hand edits to registered templates as new
+// versions. loadTemplates only reads, since the server and auto-match
+// workers load templates concurrently; this runs from single-threaded
+// entry points instead.
+func recordTemplateEdits() error {
+	paths, err := filepath.Glob(filepath.Join(templatesDir(), "*.tex"))
+	if err != nil {
+		return err
+	}
+	for _, p := range paths {
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		if _, err := recordTemplateVersion(strings.TrimSuffix(filepath.Base(p), ".tex"), string(src)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func findLegacyTemplates() []string {
+	templates, _ := filepath.Glob("resume.template*.tex")
+	if len(templates) == 0 {
+		home, _ := os.UserHomeDir()
+		templates, _ = filepath.Glob(filepath.Join(home, ".resumectl", "resume.template*.tex"))
+	}
+	return templates
+}
+
+func findTemplate(label string) (Template, error) {
+	templates, err := loadTemplates()
+	if err != nil {
+		return Template{}, err
+	}
+	for _, t := range templates {
+		if t.Label == label && filepath.Dir(t.Path) == templatesDir() {
+			return t, nil
+		}
+	}
+	return Template{}, fmt.Errorf("no template labelled %q (see 'resumectl template list')", label)
+}
+
+func templateVersions(label string) ([]templateVersion, error) {
+	paths, err := filepath.Glob(filepath.Join(templateHistoryDir(label), "*.tex"))
+	if err != nil {
+		return nil, err
+	}
+	var versions []templateVersion
+	for _, p := range paths {
+		n, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(p), ".tex"))
+		if err != nil {
+			continue
+		}
+		src, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		v := templateVersion{N: n, Path: p, Hash: contentHash(string(src))}
+		if info, err := os.Stat(p); err == nil {
+			v.Time = info.ModTime()
+		}
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].N < versions[j].N })
+	return versions, nil
+}
+
+// recordTemplateVersion saves src as the next version of label unless it is
+// already the latest, and returns the latest version number.
+func recordTemplateVersion(label, src string) (int, error) {
+	versions, err := templateVersions(label)
+	if err != nil {
+		return 0, err
+	}
+	next := 1
+	if len(versions) > 0 {
+		last := versions[len(versions)-1]
+		if last.Hash == contentHash(src) {
+			return last.N, nil
+		}
+		next = last.N + 1
+	}
+	if err := os.MkdirAll(templateHistoryDir(label), 0755); err != nil {
+		return 0, err
+	}
+	path := filepath.Join(templateHistoryDir(label), fmt.Sprintf("%d.tex", next))
+	return next, os.WriteFile(path, []byte(src), 0644)
+}
+
+// findTemplateByHash returns the template, or past version of one, whose
+// content hash is hash.
+func findTemplateByHash(hash string) string {
+	templates, _ := loadTemplates()
+	paths := findLegacyTemplates()
+	for _, t := range templates {
+		paths = append(paths, t.Path)
+	}
+	history, _ := filepath.Glob(filepath.Join(templatesDir(), ".history", "*", "*.tex"))
+	paths = append(paths, history...)
+	for _, p := range paths {
+		if src, err := os.ReadFile(p); err == nil && contentHash(string(src)) == hash {
+			return p
+		}
+	}
+	return ""
+}
+
+// writeTemplate saves src as the current version of label.
+func writeTemplate(label, src string) (int, error) {
+	if err := os.MkdirAll(templatesDir(), 0755); err != nil {
+		return 0, err
+	}
+	if err := os.WriteFile(filepath.Join(templatesDir(), label+".tex"), []byte(src), 0644); err != nil {
+		return 0, err
+	}
+	return recordTemplateVersion(label, src)
+}
+
+// applyMetaFlags overrides meta with the metadata flags that were set.
+func applyMetaFlags(cmd *cobra.Command, meta *TemplateMeta) {
+	if cmd.Flags().Changed("focus") {
+		meta.Focus = templateFocus
+	}
+	if cmd.Flags().Changed("roles") {
+		meta.Roles = templateRoles
+	}
+	if cmd.Flags().Changed("keywords") {
+		meta.Keywords = templateKeywords
+	}
+}
+
+func runTemplateList(cmd *cobra.Command, args []string) {
+	templates, err := loadTemplates()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading templates: %v\n", err)
+		os.Exit(1)
+	}
+	if len(templates) == 0 {
+		fmt.Println("No templates. Register one with: resumectl template add resume.tex --label <label>")
+		return
+	}
+
+	fmt.Printf("%-20s %-20s %-8s %-10s %s\n", "Label", "Focus", "Versions", "Edited", "Roles")
+	fmt.Println(strings.Repeat("-", 90))
+	for _, t := range templates {
+		versions := "-"
+		if filepath.Dir(t.Path) == templatesDir() {
+			v, _ := templateVersions(t.Label)
+			versions = strconv.Itoa(len(v))
+		}
+		fmt.Printf("%-20s %-20s %-8s %-10s %s\n", truncate(t.Label, 20), truncate(t.Meta.Focus, 20), versions,
+			t.Meta.Edited.Format(time.DateOnly), strings.Join(t.Meta.Roles, ", "))
+	}
+	if filepath.Dir(templates[0].Path) != templatesDir() {
+		fmt.Printf("\n%s Found by filename; register them with 'resumectl template add <file>'\n", color.YellowString("⚠"))
+	}
+}
+
+func runTemplateAdd(cmd *cobra.Command, args []string) {
+	src, err := os.ReadFile(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+	meta, body := parseFrontMatter(string(src))
+	if _, err := parseResume(body); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s is not a resume template: %v\n", args[0], err)
+		os.Exit(1)
+	}
+
+	label := templateAddLabel
+	if label == "" {
+		label = templateLabel(args[0])
+	}
+	if !templateLabelRe.MatchString(label) {
+		fmt.Fprintf(os.Stderr, "Invalid label %q: use letters, digits, '.', '_' and '-' (or pass --label)\n", label)
+		os.Exit(1)
+	}
+	applyMetaFlags(cmd, &meta)
+	if meta.Focus == "" {
+		meta.Focus = label
+	}
+	meta.Edited = time.Now()
+
+	_, statErr := os.Stat(filepath.Join(templatesDir(), label+".tex"))
+	version, err := writeTemplate(label, withFrontMatter(meta, body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving template: %v\n", err)
+		os.Exit(1)
+	}
+	verb := "Added"
+	if statErr == nil {
+		verb = "Updated"
+	}
+	fmt.Printf("%s %s %s (version %d)\n", color.GreenString("✓"), verb, label, version)
+}
+
+func runTemplateShow(cmd *cobra.Command, args []string) {
+	t, err := findTemplate(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	versions, err := templateVersions(t.Label)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		os.Exit(1)
+	}
+
+	if templateShowVersion > 0 {
+		for _, v := range versions {
+			if v.N == templateShowVersion {
+				src, err := os.ReadFile(v.Path)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				fmt.Print(string(src))
+				return
+			}
+		}
+		fmt.Fprintf(os.Stderr, "Error: %s has no version %d\n", t.Label, templateShowVersion)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%s\n", color.New(color.Bold).Sprint(t.Label))
+	fmt.Printf("  Path:     %s\n", t.Path)
+	fmt.Printf("  Focus:    %s\n", t.Meta.Focus)
+	fmt.Printf("  Roles:    %s\n", strings.Join(t.Meta.Roles, ", "))
+	fmt.Printf("  Keywords: %s\n", strings.Join(t.Meta.Keywords, ", "))
+	fmt.Printf("  Edited:   %s\n", t.Meta.Edited.Format(time.DateOnly))
+	fmt.Printf("\nVersions:\n")
+	for i := len(versions) - 1; i >= 0; i-- {
+		v := versions[i]
+		fmt.Printf("  %3d  %s  %s\n", v.N, v.Time.Format("2006-01-02 15:04"), v.Hash)
+	}
+}
+
+func runTemplateLabel(cmd *cobra.Command, args []string) {
+	t, err := findTemplate(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !cmd.Flags().Changed("focus") && !cmd.Flags().Changed("roles") && !cmd.Flags().Changed("keywords") && templateRename == "" {
+		fmt.Fprintf(os.Stderr, "Nothing to change: pass --focus, --roles, --keywords or --rename\n")
+		os.Exit(1)
+	}
+
+	src, err := os.ReadFile(t.Path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	label := t.Label
+	if templateRename != "" && templateRename != label {
+		if !templateLabelRe.MatchString(templateRename) {
+			fmt.Fprintf(os.Stderr, "Invalid label %q: use letters, digits, '.', '_' and '-'\n", templateRename)
+			os.Exit(1)
+		}
+		if _, err := os.Stat(filepath.Join(templatesDir(), templateRename+".tex")); err == nil {
+			fmt.Fprintf(os.Stderr, "A template labelled %q already exists\n", templateRename)
+			os.Exit(1)
+		}
+		if err := os.Rename(templateHistoryDir(label), templateHistoryDir(templateRename)); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error moving history: %v\n", err)
+			os.Exit(1)
+		}
+		label = templateRename
+	}
+
+	meta, body := parseFrontMatter(string(src))
+	if meta.Focus == "" {
+		meta.Focus = t.Meta.Focus
+	}
+	applyMetaFlags(cmd, &meta)
+	meta.Edited = time.Now()
+
+	// The old file goes only once the new one is saved, so a failed write
+	// leaves the template where it was.
+	version, err := writeTemplate(label, withFrontMatter(meta, body))
+	if err != nil {
+		if label != t.Label {
+			os.Rename(templateHistoryDir(label), templateHistoryDir(t.Label))
+		}
+		fmt.Fprintf(os.Stderr, "Error saving template: %v\n", err)
+		os.Exit(1)
+	}
+	if label != t.Label {
+		if err := os.Remove(t.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: saved as %s but could not remove %s: %v\n", label, t.Path, err)
+		}
+	}
+	fmt.Printf("%s Updated %s (version %d)\n", color.GreenString("✓"), label, version)
+}
+
+func runTemplateRemove(cmd *cobra.Command, args []string) {
+	t, err := findTemplate(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.Remove(t.Path); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if templatePurge {
+		if err := os.RemoveAll(templateHistoryDir(t.Label)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting history: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s Removed %s and its history\n", color.GreenString("✓"), t.Label)
+		return
+	}
+	fmt.Printf("%s Removed %s (history kept in %s)\n", color.GreenString("✓"), t.Label, templateHistoryDir(t.Label))
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrontMatter(t *testing.T) {
+	meta := TemplateMeta{
+		Focus:    "data platform",
+		Roles:    []string{"Data Engineer", "Staff Data Engineer"},
+		Keywords: []string{"Kafka", "Snowflake"},
+		Edited:   time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC),
+	}
+	src := withFrontMatter(meta, sampleResume)
+	if !strings.HasPrefix(src, "% ---\n% focus: data platform\n% roles: Data Engineer, Staff Data Engineer\n") {
+		t.Errorf("front matter:\n%s", src[:200])
+	}
+
+	got, body := parseFrontMatter(src)
+	if body != sampleResume {
+		t.Errorf("body changed:\n%s", body)
+	}
+	if got.Focus != meta.Focus || strings.Join(got.Roles, "|") != "Data Engineer|Staff Data Engineer" ||
+		strings.Join(got.Keywords, "|") != "Kafka|Snowflake" || !got.Edited.Equal(meta.Edited) {
+		t.Errorf("got %+v, want %+v", got, meta)
+	}
+	if _, err := parseResume(src); err != nil {
+		t.Errorf("template with front matter doesn't parse: %v", err)
+	}
+
+	meta.Focus = "streaming"
+	if again := withFrontMatter(meta, src); strings.Count(again, frontMatterFence) != 2 || !strings.Contains(again, "% focus: streaming\n") {
+		t.Errorf("front matter not replaced:\n%s", again[:200])
+	}
+
+	for _, src := range []string{sampleResume, "% ---\n% focus: x\n" + sampleResume, ""} {
+		if got, body := parseFrontMatter(src); got.Focus != "" || body != src {
+			t.Errorf("parseFrontMatter(%.20q) = %+v", src, got)
+		}
+	}
+}
+
+func TestTemplateRegistry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	templates, err := loadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 0 {
+		t.Fatalf("got %d templates in an empty home", len(templates))
+	}
+
+	v1 := withFrontMatter(TemplateMeta{Focus: "data platform"}, sampleResume)
+	if n, err := writeTemplate("data", v1); err != nil || n != 1 {
+		t.Fatalf("writeTemplate = %d, %v", n, err)
+	}
+	if n, err := writeTemplate("data", v1); err != nil || n != 1 {
+		t.Errorf("unchanged template got version %d, %v", n, err)
+	}
+	if _, err := writeTemplate("backend", withFrontMatter(TemplateMeta{Focus: "backend"}, sampleResume)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Loading only reads; a hand edit becomes version 2 once edits are
+	// recorded.
+	v2 := strings.Replace(v1, "Mentored 4 engineers", "Mentored 5 engineers", 1)
+	if err := os.WriteFile(filepath.Join(templatesDir(), "data.tex"), []byte(v2), 0644); err != nil {
+		t.Fatal(err)
+	}
+	templates, err = loadTemplates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if versions, _ := templateVersions("data"); len(versions) != 1 {
+		t.Fatalf("loadTemplates recorded a version: %+v", versions)
+	}
+	if err := recordTemplateEdits(); err != nil {
+		t.Fatal(err)
+	}
+	var labels []string
+	for _, tmpl := range templates {
+		labels = append(labels, tmpl.Label+":"+tmpl.Meta.Focus)
+	}
+	if got := strings.Join(labels, ","); got != "backend:backend,data:data platform" {
+		t.Errorf("templates = %s", got)
+	}
+	versions, err := templateVersions("data")
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("versions = %+v, %v", versions, err)
+	}
+
+	if got := findTemplateByHash(contentHash(v1)); got != versions[0].Path {
+		t.Errorf("old version found at %q, want %q", got, versions[0].Path)
+	}
+	if got := findTemplateByHash(contentHash(v2)); got != filepath.Join(templatesDir(), "data.tex") {
+		t.Errorf("current version found at %q", got)
+	}
+	if got := findTemplateByHash("000000000000"); got != "" {
+		t.Errorf("unknown hash found at %q", got)
+	}
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"regexp"
@@ -10,21 +11,27 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
-func scanWeb3Career(keywords []string, maxAgeDays int) ([]ScanResult, error) {
-	var allResults []ScanResult
-	seen := make(map[string]bool)
+func scanWeb3Career(ctx context.Context, keywords []string, maxAgeDays int) ([]ScanResult, error) {
+	pages := make([][]ScanResult, len(keywords))
 
-	for _, keyword := range keywords {
-		keyword = strings.TrimSpace(strings.ToLower(keyword))
+	runPool(ctx, scanConcurrency, len(keywords), func(i int) {
+		keyword := strings.TrimSpace(strings.ToLower(keywords[i]))
 		keyword = strings.ReplaceAll(keyword, " ", "-")
 		searchURL := fmt.Sprintf("https://web3.career/%s-jobs", keyword)
 
-		results, err := fetchWeb3CareerPage(searchURL, maxAgeDays)
+		results, err := fetchWeb3CareerPage(ctx, searchURL, maxAgeDays)
 		if err != nil {
-			fmt.Printf("  Warning: %s - %v\n", keyword, err)
-			continue
+			if ctx.Err() == nil {
+				fmt.Fprintf(scanLog, "  Warning: %s - %v\n", keyword, err)
+			}
+			return
 		}
+		pages[i] = results
+	})
 
+	var allResults []ScanResult
+	seen := make(map[string]bool)
+	for _, results := range pages {
 		for _, r := range results {
 			if !seen[r.URL] {
 				seen[r.URL] = true
@@ -36,8 +43,7 @@ func scanWeb3Career(keywords []string, maxAgeDays int) ([]ScanResult, error) {
 	return allResults, nil
 }
 
-func fetchWeb3CareerPage(searchURL string, maxAgeDays int) ([]ScanResult, error) {
-
+func fetchWeb3CareerPage(ctx context.Context, searchURL string, maxAgeDays int) ([]ScanResult, error) {
 	req, err := http.NewRequest("GET", searchURL, nil)
 	if err != nil {
 		return nil, err
@@ -45,7 +51,7 @@ func fetchWeb3CareerPage(searchURL string, maxAgeDays int) ([]ScanResult, error)
 	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36")
 
 	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := limitedDo(ctx, client, req)
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +121,7 @@ func fetchWeb3CareerPage(searchURL string, maxAgeDays int) ([]ScanResult, error)
 			AgeDays:  ageDays,
 			Location: location,
 			Salary:   salary,
+			Source:   "web3",
 		})
 	})
 
@@ -173,6 +180,7 @@ func scanWeb3CareerFallback(doc *goquery.Document, maxAgeDays int) ([]ScanResult
 			URL:     jobURL,
 			Age:     ageText,
 			AgeDays: ageDays,
+			Source:  "web3",
 		})
 	})
 